@@ -9,18 +9,30 @@ import (
 
 	"github.com/vmessocket/vmessocket/common"
 	"github.com/vmessocket/vmessocket/common/signal/done"
+	"github.com/vmessocket/vmessocket/core"
 	"github.com/vmessocket/vmessocket/features/outbound"
 )
 
 type Commander struct {
 	sync.Mutex
+	tag      string
+	listen   string
 	ohm      outbound.Manager
 	server   *grpc.Server
 	services []Service
 }
 
 func NewCommander(ctx context.Context, config *Config) (*Commander, error) {
-	c := &Commander{}
+	if len(config.Tag) == 0 && len(config.Listen) == 0 {
+		return nil, newError("api requires a tag or a listen address")
+	}
+	c := &Commander{
+		tag:    config.Tag,
+		listen: config.Listen,
+	}
+	common.Must(core.RequireFeatures(ctx, func(om outbound.Manager) {
+		c.ohm = om
+	}))
 	for _, rawConfig := range config.Service {
 		config, err := rawConfig.GetInstance()
 		if err != nil {
@@ -49,23 +61,38 @@ func (c *Commander) Close() error {
 	return nil
 }
 
+func (c *Commander) serve(server *grpc.Server, listener net.Listener) {
+	if err := server.Serve(listener); err != nil {
+		newError("failed to start grpc server").Base(err).AtError().WriteToLog()
+	}
+}
+
 func (c *Commander) Start() error {
 	c.Lock()
 	c.server = grpc.NewServer()
 	for _, service := range c.services {
 		service.Register(c.server)
 	}
+	server := c.server
 	c.Unlock()
+	if len(c.listen) > 0 {
+		listener, err := net.Listen("tcp", c.listen)
+		if err != nil {
+			return newError("failed to listen on ", c.listen).Base(err)
+		}
+		newError("api listening on ", listener.Addr()).AtInfo().WriteToLog()
+		go c.serve(server, listener)
+	}
+	if len(c.tag) == 0 {
+		return nil
+	}
 	listener := &OutboundListener{
 		buffer: make(chan net.Conn, 4),
 		done:   done.New(),
 	}
-	go func() {
-		if err := c.server.Serve(listener); err != nil {
-			newError("failed to start grpc server").Base(err).AtError().WriteToLog()
-		}
-	}()
+	go c.serve(server, listener)
 	return c.ohm.AddHandler(context.Background(), &Outbound{
+		tag:      c.tag,
 		listener: listener,
 	})
 }
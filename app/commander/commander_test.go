@@ -0,0 +1,67 @@
+package commander_test
+
+import (
+	"context"
+	gonet "net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/vmessocket/vmessocket/app/commander"
+	"github.com/vmessocket/vmessocket/app/dispatcher"
+	"github.com/vmessocket/vmessocket/app/proxyman"
+	_ "github.com/vmessocket/vmessocket/app/proxyman/inbound"
+	_ "github.com/vmessocket/vmessocket/app/proxyman/outbound"
+	"github.com/vmessocket/vmessocket/common"
+	"github.com/vmessocket/vmessocket/common/serial"
+	"github.com/vmessocket/vmessocket/core"
+	"github.com/vmessocket/vmessocket/transport/internet/tls/command"
+)
+
+func pickAddress() string {
+	l, err := gonet.Listen("tcp", "127.0.0.1:0")
+	common.Must(err)
+	defer l.Close()
+	return l.Addr().String()
+}
+
+func TestCommanderListen(t *testing.T) {
+	listen := pickAddress()
+	instance, err := core.New(&core.Config{
+		App: []*serial.TypedMessage{
+			serial.ToTypedMessage(&dispatcher.Config{}),
+			serial.ToTypedMessage(&proxyman.InboundConfig{}),
+			serial.ToTypedMessage(&proxyman.OutboundConfig{}),
+			serial.ToTypedMessage(&commander.Config{
+				Listen: listen,
+				Service: []*serial.TypedMessage{
+					serial.ToTypedMessage(&command.Config{}),
+				},
+			}),
+		},
+	})
+	common.Must(err)
+	common.Must(instance.Start())
+	defer instance.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	conn, err := grpc.DialContext(ctx, listen, grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithBlock())
+	common.Must(err)
+	defer conn.Close()
+	if _, err := command.NewCertificateServiceClient(conn).ReloadCertificates(ctx, &command.ReloadCertificatesRequest{}); err != nil {
+		t.Fatal(err)
+	}
+	common.Must(instance.Close())
+	if conn, err := gonet.DialTimeout("tcp", listen, time.Second); err == nil {
+		conn.Close()
+		t.Error("api still listening after close")
+	}
+}
+
+func TestCommanderRequiresTagOrListen(t *testing.T) {
+	if _, err := commander.NewCommander(context.Background(), &commander.Config{}); err == nil {
+		t.Error("created a commander that cannot be reached")
+	}
+}
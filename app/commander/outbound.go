@@ -11,6 +11,7 @@ import (
 )
 
 type Outbound struct {
+	tag      string
 	access   sync.RWMutex
 	closed   bool
 	listener *OutboundListener
@@ -89,3 +90,7 @@ func (co *Outbound) Start() error {
 	co.access.Unlock()
 	return nil
 }
+
+func (co *Outbound) Tag() string {
+	return co.tag
+}
@@ -23,7 +23,8 @@ type cachedReader struct {
 }
 
 type DefaultDispatcher struct {
-	ohm    outbound.Manager
+	ohm   outbound.Manager
+	rules []*RoutingRule
 }
 
 func (r *cachedReader) Cache(b *buf.Buffer) {
@@ -84,6 +85,7 @@ func (d *DefaultDispatcher) getLink(ctx context.Context) (*transport.Link, *tran
 
 func (d *DefaultDispatcher) Init(config *Config, om outbound.Manager) error {
 	d.ohm = om
+	d.rules = config.Rule
 	return nil
 }
 
@@ -125,6 +127,17 @@ func (r *cachedReader) ReadMultiBufferTimeout(timeout time.Duration) (buf.MultiB
 
 func (d *DefaultDispatcher) routedDispatch(ctx context.Context, link *transport.Link, destination net.Destination) {
 	var handler outbound.Handler
+	for _, rule := range d.rules {
+		if !rule.Apply(ctx, destination) {
+			continue
+		}
+		if handler = d.ohm.GetHandler(rule.OutboundTag); handler != nil {
+			newError("taking detour [", rule.OutboundTag, "] for [", destination, "]").WriteToLog(session.ExportIDToError(ctx))
+		} else {
+			newError("non existing outbound tag: ", rule.OutboundTag).AtWarning().WriteToLog(session.ExportIDToError(ctx))
+		}
+		break
+	}
 	if handler == nil {
 		handler = d.ohm.GetDefaultHandler()
 	}
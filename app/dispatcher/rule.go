@@ -0,0 +1,43 @@
+package dispatcher
+
+import (
+	"context"
+	"strings"
+
+	"github.com/vmessocket/vmessocket/common/net"
+	"github.com/vmessocket/vmessocket/common/session"
+)
+
+func matchDomain(domain string, patterns []string) bool {
+	domain = strings.ToLower(domain)
+	for _, pattern := range patterns {
+		pattern = strings.ToLower(pattern)
+		if domain == pattern || strings.HasSuffix(domain, "."+pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchString(value string, candidates []string) bool {
+	for _, candidate := range candidates {
+		if strings.EqualFold(value, candidate) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *RoutingRule) Apply(ctx context.Context, destination net.Destination) bool {
+	inbound := session.InboundFromContext(ctx)
+	if len(r.InboundTag) > 0 && (inbound == nil || !matchString(inbound.Tag, r.InboundTag)) {
+		return false
+	}
+	if len(r.UserEmail) > 0 && (inbound == nil || inbound.User == nil || !matchString(inbound.User.Email, r.UserEmail)) {
+		return false
+	}
+	if len(r.Domain) > 0 && (!destination.Address.Family().IsDomain() || !matchDomain(destination.Address.Domain(), r.Domain)) {
+		return false
+	}
+	return true
+}
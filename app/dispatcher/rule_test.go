@@ -0,0 +1,79 @@
+package dispatcher
+
+import (
+	"context"
+	"testing"
+
+	"github.com/vmessocket/vmessocket/common/net"
+	"github.com/vmessocket/vmessocket/common/protocol"
+	"github.com/vmessocket/vmessocket/common/session"
+)
+
+func TestRoutingRuleApply(t *testing.T) {
+	inbound := &session.Inbound{
+		Tag: "socks",
+		User: &protocol.MemoryUser{
+			Email: "Alice@example.com",
+		},
+	}
+	testCases := []struct {
+		rule        *RoutingRule
+		inbound     *session.Inbound
+		destination net.Destination
+		match       bool
+	}{
+		{
+			rule:        &RoutingRule{InboundTag: []string{"socks"}},
+			inbound:     inbound,
+			destination: net.TCPDestination(net.DomainAddress("example.com"), 80),
+			match:       true,
+		},
+		{
+			rule:        &RoutingRule{InboundTag: []string{"http"}},
+			inbound:     inbound,
+			destination: net.TCPDestination(net.DomainAddress("example.com"), 80),
+		},
+		{
+			rule:        &RoutingRule{InboundTag: []string{"socks"}},
+			destination: net.TCPDestination(net.DomainAddress("example.com"), 80),
+		},
+		{
+			rule:        &RoutingRule{UserEmail: []string{"alice@example.com"}},
+			inbound:     inbound,
+			destination: net.TCPDestination(net.LocalHostIP, 80),
+			match:       true,
+		},
+		{
+			rule:        &RoutingRule{UserEmail: []string{"bob@example.com"}},
+			inbound:     inbound,
+			destination: net.TCPDestination(net.LocalHostIP, 80),
+		},
+		{
+			rule:        &RoutingRule{Domain: []string{"example.com"}},
+			destination: net.TCPDestination(net.DomainAddress("www.Example.com"), 443),
+			match:       true,
+		},
+		{
+			rule:        &RoutingRule{Domain: []string{"example.com"}},
+			destination: net.TCPDestination(net.DomainAddress("badexample.com"), 443),
+		},
+		{
+			rule:        &RoutingRule{Domain: []string{"example.com"}},
+			destination: net.TCPDestination(net.LocalHostIP, 443),
+		},
+		{
+			rule:        &RoutingRule{InboundTag: []string{"socks"}, Domain: []string{"example.org"}},
+			inbound:     inbound,
+			destination: net.TCPDestination(net.DomainAddress("example.com"), 443),
+		},
+	}
+	for _, tc := range testCases {
+		ctx := context.Background()
+		if tc.inbound != nil {
+			ctx = session.ContextWithInbound(ctx, tc.inbound)
+		}
+		if match := tc.rule.Apply(ctx, tc.destination); match != tc.match {
+			t.Error("rule ", tc.rule, " on ", tc.destination, ": expected ", tc.match, ", got ", match)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package proxyman
+
+import "github.com/vmessocket/vmessocket/common/net"
+
+func (s *AllocationStrategy) GetConcurrencyValue() uint32 {
+	if s == nil || s.Concurrency == nil || s.Concurrency.Value == 0 {
+		return 3
+	}
+	return s.Concurrency.Value
+}
+
+func (s *AllocationStrategy) GetRefreshValue() uint32 {
+	if s == nil || s.Refresh == nil || s.Refresh.Value == 0 {
+		return 5
+	}
+	return s.Refresh.Value
+}
+
+func (b *MultiplexingBypass) Match(dest net.Destination) bool {
+	if len(b.Network) > 0 && !net.HasNetwork(b.Network, dest.Network) {
+		return false
+	}
+	if b.Port != nil && len(b.Port.Range) > 0 {
+		for _, r := range b.Port.Range {
+			if r.Contains(dest.Port) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func (c *MultiplexingConfig) ShouldBypass(dest net.Destination) bool {
+	for _, b := range c.Bypass {
+		if b.Match(dest) {
+			return true
+		}
+	}
+	return false
+}
@@ -14,12 +14,13 @@ import (
 )
 
 type AlwaysOnInboundHandler struct {
+	tag     string
 	proxy   proxy.Inbound
 	workers []worker
 	mux     *mux.Server
 }
 
-func NewAlwaysOnInboundHandler(ctx context.Context, receiverConfig *proxyman.ReceiverConfig, proxyConfig interface{}) (*AlwaysOnInboundHandler, error) {
+func NewAlwaysOnInboundHandler(ctx context.Context, tag string, receiverConfig *proxyman.ReceiverConfig, proxyConfig interface{}) (*AlwaysOnInboundHandler, error) {
 	rawProxy, err := common.CreateObject(ctx, proxyConfig)
 	if err != nil {
 		return nil, err
@@ -29,6 +30,7 @@ func NewAlwaysOnInboundHandler(ctx context.Context, receiverConfig *proxyman.Rec
 		return nil, newError("not an inbound proxy.")
 	}
 	h := &AlwaysOnInboundHandler{
+		tag:   tag,
 		proxy: p,
 		mux:   mux.NewServer(ctx),
 	}
@@ -47,6 +49,7 @@ func NewAlwaysOnInboundHandler(ctx context.Context, receiverConfig *proxyman.Rec
 			if net.HasNetwork(nl, net.Network_TCP) {
 				newError("creating stream worker on ", address, ":", port).AtDebug().WriteToLog()
 				worker := &tcpWorker{
+					tag:          tag,
 					address:      address,
 					port:         net.Port(port),
 					proxy:        p,
@@ -60,6 +63,7 @@ func NewAlwaysOnInboundHandler(ctx context.Context, receiverConfig *proxyman.Rec
 			if net.HasNetwork(nl, net.Network_UDP) {
 				worker := &udpWorker{
 					ctx:        ctx,
+					tag:        tag,
 					proxy:      p,
 					address:    address,
 					port:       net.Port(port),
@@ -97,6 +101,11 @@ func (h *AlwaysOnInboundHandler) GetRandomInboundProxy() (interface{}, net.Port,
 }
 
 func (h *AlwaysOnInboundHandler) Start() error {
+	if p, ok := h.proxy.(common.Runnable); ok {
+		if err := p.Start(); err != nil {
+			return newError("failed to start inbound proxy").Base(err)
+		}
+	}
 	for _, worker := range h.workers {
 		if err := worker.Start(); err != nil {
 			return err
@@ -104,3 +113,7 @@ func (h *AlwaysOnInboundHandler) Start() error {
 	}
 	return nil
 }
+
+func (h *AlwaysOnInboundHandler) Tag() string {
+	return h.tag
+}
@@ -0,0 +1,245 @@
+package inbound
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/vmessocket/vmessocket/app/proxyman"
+	"github.com/vmessocket/vmessocket/common"
+	"github.com/vmessocket/vmessocket/common/dice"
+	"github.com/vmessocket/vmessocket/common/mux"
+	"github.com/vmessocket/vmessocket/common/net"
+	"github.com/vmessocket/vmessocket/common/task"
+	"github.com/vmessocket/vmessocket/proxy"
+	"github.com/vmessocket/vmessocket/transport/internet"
+)
+
+const retireDelay = time.Minute
+
+type DynamicInboundHandler struct {
+	tag            string
+	ctx            context.Context
+	proxy          proxy.Inbound
+	receiverConfig *proxyman.ReceiverConfig
+	stream         *internet.MemoryStreamConfig
+	portMutex      sync.Mutex
+	portsInUse     map[net.Port]bool
+	workerMutex    sync.RWMutex
+	workers        []worker
+	retiring       []worker
+	lastRefresh    time.Time
+	retireDelay    time.Duration
+	mux            *mux.Server
+	task           *task.Periodic
+}
+
+type sharedInbound struct {
+	proxy.Inbound
+}
+
+func NewDynamicInboundHandler(ctx context.Context, tag string, receiverConfig *proxyman.ReceiverConfig, proxyConfig interface{}) (*DynamicInboundHandler, error) {
+	pr := receiverConfig.PortRange
+	if pr == nil {
+		return nil, newError("random allocation requires a port range")
+	}
+	concurrency := receiverConfig.AllocationStrategy.GetConcurrencyValue()
+	if concurrency*2 > pr.To-pr.From+1 {
+		return nil, newError("not enough ports in ", pr.From, "-", pr.To, " for concurrency ", concurrency)
+	}
+	mss, err := internet.ToMemoryStreamConfig(receiverConfig.StreamSettings)
+	if err != nil {
+		return nil, newError("failed to parse stream config").Base(err).AtWarning()
+	}
+	rawProxy, err := common.CreateObject(ctx, proxyConfig)
+	if err != nil {
+		return nil, err
+	}
+	p, ok := rawProxy.(proxy.Inbound)
+	if !ok {
+		return nil, newError("not an inbound proxy.")
+	}
+	h := &DynamicInboundHandler{
+		tag:            tag,
+		ctx:            ctx,
+		proxy:          p,
+		receiverConfig: receiverConfig,
+		stream:         mss,
+		portsInUse:     make(map[net.Port]bool),
+		retireDelay:    retireDelay,
+		mux:            mux.NewServer(ctx),
+	}
+	h.task = &task.Periodic{
+		Interval: h.refreshInterval(),
+		Execute:  h.refresh,
+	}
+	return h, nil
+}
+
+func (h *DynamicInboundHandler) allocatePort() net.Port {
+	from := int(h.receiverConfig.PortRange.From)
+	delta := int(h.receiverConfig.PortRange.To) - from + 1
+	h.portMutex.Lock()
+	defer h.portMutex.Unlock()
+	for {
+		port := net.Port(from + dice.Roll(delta))
+		if !h.portsInUse[port] {
+			h.portsInUse[port] = true
+			return port
+		}
+	}
+}
+
+func (h *DynamicInboundHandler) Close() error {
+	h.task.Close()
+	h.workerMutex.Lock()
+	workers := h.workers
+	retiring := h.retiring
+	h.workers = nil
+	h.retiring = nil
+	h.workerMutex.Unlock()
+	h.closeWorkers(workers)
+	h.closeWorkers(retiring)
+	return common.Close(h.proxy)
+}
+
+func (h *DynamicInboundHandler) closeWorkers(workers []worker) {
+	for _, w := range workers {
+		if err := w.Close(); err != nil {
+			newError("failed to close worker on port ", w.Port()).Base(err).AtDebug().WriteToLog()
+		}
+	}
+	h.portMutex.Lock()
+	for _, w := range workers {
+		delete(h.portsInUse, w.Port())
+	}
+	h.portMutex.Unlock()
+}
+
+func (h *DynamicInboundHandler) GetInbound() proxy.Inbound {
+	return h.proxy
+}
+
+func (h *DynamicInboundHandler) GetRandomInboundProxy() (interface{}, net.Port, int) {
+	h.workerMutex.RLock()
+	defer h.workerMutex.RUnlock()
+	if len(h.workers) == 0 {
+		return nil, 0, 0
+	}
+	w := h.workers[dice.Roll(len(h.workers))]
+	remaining := h.refreshInterval() - time.Since(h.lastRefresh)
+	availableMin := int(remaining / time.Minute)
+	if availableMin < 1 {
+		availableMin = 1
+	}
+	return h.proxy, w.Port(), availableMin
+}
+
+func (h *DynamicInboundHandler) refresh() error {
+	address := h.receiverConfig.Listen.AsAddress()
+	if address == nil {
+		address = net.AnyIP
+	}
+	p := sharedInbound{h.proxy}
+	nl := p.Network()
+	concurrency := h.receiverConfig.AllocationStrategy.GetConcurrencyValue()
+	workers := make([]worker, 0, concurrency)
+	for i := uint32(0); i < concurrency; i++ {
+		port := h.allocatePort()
+		var started []worker
+		if net.HasNetwork(nl, net.Network_TCP) {
+			started = append(started, &tcpWorker{
+				tag:          h.tag,
+				address:      address,
+				port:         port,
+				proxy:        p,
+				stream:       h.stream,
+				recvOrigDest: h.receiverConfig.ReceiveOriginalDestination,
+				dispatcher:   h.mux,
+				ctx:          h.ctx,
+			})
+		}
+		if net.HasNetwork(nl, net.Network_UDP) {
+			started = append(started, &udpWorker{
+				ctx:        h.ctx,
+				tag:        h.tag,
+				proxy:      p,
+				address:    address,
+				port:       port,
+				dispatcher: h.mux,
+				stream:     h.stream,
+			})
+		}
+		var failed error
+		for idx, w := range started {
+			if err := w.Start(); err != nil {
+				failed = err
+				for _, sw := range started[:idx] {
+					sw.Close()
+				}
+				break
+			}
+		}
+		if failed != nil {
+			newError("failed to listen on port ", port).Base(failed).AtWarning().WriteToLog()
+			h.portMutex.Lock()
+			delete(h.portsInUse, port)
+			h.portMutex.Unlock()
+			continue
+		}
+		newError("listening on dynamic port ", address, ":", port).AtDebug().WriteToLog()
+		workers = append(workers, started...)
+	}
+	if len(workers) == 0 {
+		newError("no dynamic port is available, keeping previous ports").AtWarning().WriteToLog()
+		return nil
+	}
+	h.workerMutex.Lock()
+	retired := h.workers
+	h.retiring = append(h.retiring, retired...)
+	h.workers = workers
+	h.lastRefresh = time.Now()
+	h.workerMutex.Unlock()
+	if len(retired) > 0 {
+		time.AfterFunc(h.retireDelay, func() {
+			h.retire(retired)
+		})
+	}
+	return nil
+}
+
+func (h *DynamicInboundHandler) refreshInterval() time.Duration {
+	return time.Minute * time.Duration(h.receiverConfig.AllocationStrategy.GetRefreshValue())
+}
+
+func (h *DynamicInboundHandler) retire(workers []worker) {
+	retired := make(map[worker]bool, len(workers))
+	for _, w := range workers {
+		retired[w] = true
+	}
+	h.workerMutex.Lock()
+	var closing, remaining []worker
+	for _, w := range h.retiring {
+		if retired[w] {
+			closing = append(closing, w)
+		} else {
+			remaining = append(remaining, w)
+		}
+	}
+	h.retiring = remaining
+	h.workerMutex.Unlock()
+	h.closeWorkers(closing)
+}
+
+func (h *DynamicInboundHandler) Start() error {
+	if p, ok := h.proxy.(common.Runnable); ok {
+		if err := p.Start(); err != nil {
+			return newError("failed to start inbound proxy").Base(err)
+		}
+	}
+	return h.task.Start()
+}
+
+func (h *DynamicInboundHandler) Tag() string {
+	return h.tag
+}
@@ -0,0 +1,134 @@
+package inbound
+
+import (
+	gonet "net"
+	"testing"
+	"time"
+
+	"github.com/vmessocket/vmessocket/app/dispatcher"
+	"github.com/vmessocket/vmessocket/app/proxyman"
+	_ "github.com/vmessocket/vmessocket/app/proxyman/outbound"
+	"github.com/vmessocket/vmessocket/common"
+	"github.com/vmessocket/vmessocket/common/net"
+	"github.com/vmessocket/vmessocket/common/serial"
+	"github.com/vmessocket/vmessocket/core"
+	vmessinbound "github.com/vmessocket/vmessocket/proxy/vmess/inbound"
+	_ "github.com/vmessocket/vmessocket/transport/internet/tcp"
+)
+
+func newDynamicHandler(t *testing.T) *DynamicInboundHandler {
+	t.Helper()
+	instance, err := core.New(&core.Config{
+		App: []*serial.TypedMessage{
+			serial.ToTypedMessage(&dispatcher.Config{}),
+			serial.ToTypedMessage(&proxyman.InboundConfig{}),
+			serial.ToTypedMessage(&proxyman.OutboundConfig{}),
+		},
+	})
+	common.Must(err)
+	h, err := core.CreateObject(instance, &core.InboundHandlerConfig{
+		ReceiverSettings: serial.ToTypedMessage(&proxyman.ReceiverConfig{
+			PortRange: &net.PortRange{From: 41000, To: 41999},
+			Listen:    net.NewIPOrDomain(net.LocalHostIP),
+			AllocationStrategy: &proxyman.AllocationStrategy{
+				Type:        proxyman.AllocationStrategy_Random,
+				Concurrency: &proxyman.AllocationStrategy_AllocationStrategyConcurrency{Value: 2},
+			},
+		}),
+		ProxySettings: serial.ToTypedMessage(&vmessinbound.Config{}),
+	})
+	common.Must(err)
+	handler := h.(*DynamicInboundHandler)
+	t.Cleanup(func() {
+		handler.Close()
+	})
+	return handler
+}
+
+func listening(port net.Port) bool {
+	conn, err := gonet.DialTimeout("tcp", gonet.JoinHostPort("127.0.0.1", port.String()), time.Second)
+	if err != nil {
+		return false
+	}
+	conn.Close()
+	return true
+}
+
+func workerPorts(h *DynamicInboundHandler) map[net.Port]bool {
+	h.workerMutex.RLock()
+	defer h.workerMutex.RUnlock()
+	ports := make(map[net.Port]bool)
+	for _, w := range h.workers {
+		ports[w.Port()] = true
+	}
+	return ports
+}
+
+func TestDynamicInboundAvailableMin(t *testing.T) {
+	h := newDynamicHandler(t)
+	common.Must(h.refresh())
+	testCases := []struct {
+		sinceRefresh time.Duration
+		want         int
+	}{
+		{0, 4},
+		{time.Minute, 3},
+		{4*time.Minute + 30*time.Second, 1},
+		{10 * time.Minute, 1},
+	}
+	for _, tc := range testCases {
+		h.workerMutex.Lock()
+		h.lastRefresh = time.Now().Add(-tc.sinceRefresh)
+		h.workerMutex.Unlock()
+		p, port, availableMin := h.GetRandomInboundProxy()
+		if p != h.GetInbound() {
+			t.Errorf("after %v: proxy differs from the handler's inbound", tc.sinceRefresh)
+		}
+		if !workerPorts(h)[port] {
+			t.Errorf("after %v: port %v is not an active port", tc.sinceRefresh, port)
+		}
+		if availableMin != tc.want {
+			t.Errorf("after %v: availableMin = %d, want %d", tc.sinceRefresh, availableMin, tc.want)
+		}
+	}
+}
+
+func TestDynamicInboundRotatesPorts(t *testing.T) {
+	h := newDynamicHandler(t)
+	h.retireDelay = 200 * time.Millisecond
+	p := h.GetInbound()
+	common.Must(h.refresh())
+	old := workerPorts(h)
+	if len(old) != 2 {
+		t.Fatalf("got %d ports, want 2", len(old))
+	}
+	common.Must(h.refresh())
+	current := workerPorts(h)
+	for port := range current {
+		if old[port] {
+			t.Errorf("port %v was reused while still retiring", port)
+		}
+		if !listening(port) {
+			t.Errorf("new port %v is not listening", port)
+		}
+	}
+	for port := range old {
+		if !listening(port) {
+			t.Errorf("retiring port %v closed before the retire delay", port)
+		}
+	}
+	if h.GetInbound() != p {
+		t.Error("refresh replaced the shared proxy")
+	}
+	time.Sleep(500 * time.Millisecond)
+	for port := range old {
+		if listening(port) {
+			t.Errorf("retired port %v is still listening", port)
+		}
+	}
+	for port := range current {
+		if !listening(port) {
+			t.Errorf("active port %v closed with the retired ones", port)
+		}
+	}
+}
@@ -39,9 +39,13 @@ func NewHandler(ctx context.Context, config *core.InboundHandlerConfig) (inbound
 	if !ok {
 		return nil, newError("not a ReceiverConfig").AtError()
 	}
+	tag := config.Tag
 	allocStrategy := receiverSettings.AllocationStrategy
 	if allocStrategy == nil || allocStrategy.Type == proxyman.AllocationStrategy_Always {
-		return NewAlwaysOnInboundHandler(ctx, receiverSettings, proxySettings)
+		return NewAlwaysOnInboundHandler(ctx, tag, receiverSettings, proxySettings)
+	}
+	if allocStrategy.Type == proxyman.AllocationStrategy_Random {
+		return NewDynamicInboundHandler(ctx, tag, receiverSettings, proxySettings)
 	}
 	return nil, newError("unknown allocation strategy: ", receiverSettings.AllocationStrategy.Type).AtError()
 }
@@ -49,7 +53,15 @@ func NewHandler(ctx context.Context, config *core.InboundHandlerConfig) (inbound
 func (m *Manager) AddHandler(ctx context.Context, handler inbound.Handler) error {
 	m.access.Lock()
 	defer m.access.Unlock()
-	m.untaggedHandler = append(m.untaggedHandler, handler)
+	tag := handler.Tag()
+	if len(tag) > 0 {
+		if _, found := m.taggedHandlers[tag]; found {
+			return newError("existing tag found: " + tag)
+		}
+		m.taggedHandlers[tag] = handler
+	} else {
+		m.untaggedHandler = append(m.untaggedHandler, handler)
+	}
 	if m.running {
 		return handler.Start()
 	}
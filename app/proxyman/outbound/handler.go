@@ -2,9 +2,11 @@ package outbound
 
 import (
 	"context"
+	"time"
 
 	"github.com/vmessocket/vmessocket/app/proxyman"
 	"github.com/vmessocket/vmessocket/common"
+	"github.com/vmessocket/vmessocket/common/mux"
 	"github.com/vmessocket/vmessocket/common/net"
 	"github.com/vmessocket/vmessocket/common/session"
 	"github.com/vmessocket/vmessocket/core"
@@ -14,16 +16,24 @@ import (
 	"github.com/vmessocket/vmessocket/transport/internet"
 )
 
+const (
+	defaultMuxConcurrency   = 8
+	defaultMuxMaxConnection = 128
+)
+
 type Handler struct {
+	tag             string
 	senderSettings  *proxyman.SenderConfig
 	streamSettings  *internet.MemoryStreamConfig
 	proxy           proxy.Outbound
 	outboundManager outbound.Manager
+	mux             *mux.ClientManager
 }
 
 func NewHandler(ctx context.Context, config *core.OutboundHandlerConfig) (outbound.Handler, error) {
 	v := core.MustFromContext(ctx)
 	h := &Handler{
+		tag:             config.Tag,
 		outboundManager: v.GetFeature(outbound.ManagerType()).(outbound.Manager),
 	}
 	if config.SenderSettings != nil {
@@ -56,6 +66,29 @@ func NewHandler(ctx context.Context, config *core.OutboundHandlerConfig) (outbou
 		return nil, newError("not an outbound handler")
 	}
 	h.proxy = proxyHandler
+	if h.senderSettings != nil && h.senderSettings.MultiplexSettings != nil && h.senderSettings.MultiplexSettings.Enabled {
+		config := h.senderSettings.MultiplexSettings
+		concurrency := config.Concurrency
+		if concurrency == 0 {
+			concurrency = defaultMuxConcurrency
+		}
+		h.mux = &mux.ClientManager{
+			Enabled: true,
+			Picker: &mux.IncrementalWorkerPicker{
+				Factory: &mux.DialingWorkerFactory{
+					Proxy:  proxyHandler,
+					Dialer: h,
+					Strategy: mux.ClientStrategy{
+						MaxConcurrency: concurrency,
+						MaxConnection:  defaultMuxMaxConnection,
+						IdleTimeout:    time.Duration(config.IdleTimeout) * time.Second,
+						MaxLifetime:    time.Duration(config.MaxLifetime) * time.Second,
+					},
+				},
+				MaxIdle: config.MaxIdleConnections,
+			},
+		}
+	}
 	return h, nil
 }
 
@@ -66,7 +99,18 @@ func (h *Handler) Address() net.Address {
 	return h.senderSettings.Via.AsAddress()
 }
 
+func (h *Handler) bypassMux(ctx context.Context) bool {
+	outbound := session.OutboundFromContext(ctx)
+	if outbound == nil {
+		return false
+	}
+	return h.senderSettings.MultiplexSettings.ShouldBypass(outbound.Target)
+}
+
 func (h *Handler) Close() error {
+	if h.mux != nil {
+		return h.mux.Close()
+	}
 	return nil
 }
 
@@ -86,6 +130,15 @@ func (h *Handler) Dial(ctx context.Context, dest net.Destination) (internet.Conn
 }
 
 func (h *Handler) Dispatch(ctx context.Context, link *transport.Link) {
+	if h.mux != nil && !h.bypassMux(ctx) {
+		if err := h.mux.Dispatch(ctx, link); err != nil {
+			err := newError("failed to process mux outbound traffic").Base(err)
+			session.SubmitOutboundErrorToOriginator(ctx, err)
+			err.WriteToLog(session.ExportIDToError(ctx))
+			common.Interrupt(link.Writer)
+		}
+		return
+	}
 	if err := h.proxy.Process(ctx, link, h); err != nil {
 		err := newError("failed to process outbound traffic").Base(err)
 		session.SubmitOutboundErrorToOriginator(ctx, err)
@@ -104,3 +157,7 @@ func (h *Handler) GetOutbound() proxy.Outbound {
 func (h *Handler) Start() error {
 	return nil
 }
+
+func (h *Handler) Tag() string {
+	return h.tag
+}
@@ -0,0 +1,109 @@
+package outbound
+
+import (
+	"context"
+	"testing"
+
+	"github.com/vmessocket/vmessocket/app/proxyman"
+	"github.com/vmessocket/vmessocket/common"
+	"github.com/vmessocket/vmessocket/common/buf"
+	"github.com/vmessocket/vmessocket/common/mux"
+	"github.com/vmessocket/vmessocket/common/net"
+	"github.com/vmessocket/vmessocket/common/session"
+	"github.com/vmessocket/vmessocket/proxy"
+	"github.com/vmessocket/vmessocket/transport"
+	"github.com/vmessocket/vmessocket/transport/internet"
+	"github.com/vmessocket/vmessocket/transport/pipe"
+)
+
+type drainingProxy struct{}
+
+type recordingPicker struct {
+	picked int
+}
+
+type recordingProxy struct {
+	targets []net.Destination
+}
+
+func newMuxHandler(p proxy.Outbound, picker mux.WorkerPicker) *Handler {
+	return &Handler{
+		senderSettings: &proxyman.SenderConfig{
+			MultiplexSettings: &proxyman.MultiplexingConfig{
+				Enabled: true,
+				Bypass: []*proxyman.MultiplexingBypass{
+					{
+						Network: []net.Network{net.Network_UDP},
+					},
+				},
+			},
+		},
+		proxy: p,
+		mux: &mux.ClientManager{
+			Enabled: true,
+			Picker:  picker,
+		},
+	}
+}
+
+func (drainingProxy) Process(ctx context.Context, link *transport.Link, dialer internet.Dialer) error {
+	return buf.Copy(link.Reader, buf.Discard)
+}
+
+func (p *recordingPicker) PickAvailable() (*mux.ClientWorker, error) {
+	p.picked++
+	return nil, newError("no worker in test")
+}
+
+func (p *recordingProxy) Process(ctx context.Context, link *transport.Link, dialer internet.Dialer) error {
+	p.targets = append(p.targets, session.OutboundFromContext(ctx).Target)
+	return nil
+}
+
+func TestHandlerCloseClosesMuxWorkers(t *testing.T) {
+	picker := &mux.IncrementalWorkerPicker{
+		Factory: &mux.DialingWorkerFactory{
+			Proxy:  drainingProxy{},
+			Dialer: new(Handler),
+		},
+	}
+	h := newMuxHandler(drainingProxy{}, picker)
+	worker, err := picker.PickAvailable()
+	common.Must(err)
+	if worker.Closed() {
+		t.Fatal("mux worker closed before the handler")
+	}
+	common.Must(h.Close())
+	if !worker.Closed() {
+		t.Fatal("mux worker is still open after the handler was closed")
+	}
+	if _, err := picker.PickAvailable(); err == nil {
+		t.Fatal("closed handler created a new mux worker")
+	}
+}
+
+func TestHandlerMuxBypass(t *testing.T) {
+	p := new(recordingProxy)
+	picker := new(recordingPicker)
+	h := newMuxHandler(p, picker)
+	testCases := []struct {
+		target net.Destination
+		mux    bool
+	}{
+		{target: net.TCPDestination(net.DomainAddress("example.com"), 443), mux: true},
+		{target: net.UDPDestination(net.DomainAddress("example.com"), 443)},
+	}
+	for _, tc := range testCases {
+		picked, direct := picker.picked, len(p.targets)
+		ctx := session.ContextWithOutbound(context.Background(), &session.Outbound{Target: tc.target})
+		reader, _ := pipe.New()
+		_, writer := pipe.New()
+		h.Dispatch(ctx, &transport.Link{Reader: reader, Writer: writer})
+		if tc.mux && (picker.picked == picked || len(p.targets) != direct) {
+			t.Error(tc.target, " did not go through mux")
+		}
+		if !tc.mux && (picker.picked != picked || len(p.targets) != direct+1 || p.targets[direct] != tc.target) {
+			t.Error(tc.target, " did not bypass mux")
+		}
+	}
+}
@@ -30,10 +30,18 @@ func New(ctx context.Context, config *proxyman.OutboundConfig) (*Manager, error)
 func (m *Manager) AddHandler(ctx context.Context, handler outbound.Handler) error {
 	m.access.Lock()
 	defer m.access.Unlock()
+	tag := handler.Tag()
+	if len(tag) > 0 {
+		if _, found := m.taggedHandler[tag]; found {
+			return newError("existing tag found: ", tag)
+		}
+		m.taggedHandler[tag] = handler
+	} else {
+		m.untaggedHandlers = append(m.untaggedHandlers, handler)
+	}
 	if m.defaultHandler == nil {
 		m.defaultHandler = handler
 	}
-	m.untaggedHandlers = append(m.untaggedHandlers, handler)
 	if m.running {
 		return handler.Start()
 	}
@@ -79,6 +87,9 @@ func (m *Manager) RemoveHandler(ctx context.Context, tag string) error {
 	m.access.Lock()
 	defer m.access.Unlock()
 	delete(m.taggedHandler, tag)
+	if m.defaultHandler != nil && m.defaultHandler.Tag() == tag {
+		m.defaultHandler = nil
+	}
 	return nil
 }
 
@@ -14,6 +14,7 @@ type Buffer struct {
 	v     []byte
 	start int32
 	end   int32
+	UDP   interface{}
 }
 
 func New() *Buffer {
@@ -134,6 +135,7 @@ func (b *Buffer) Release() {
 	p := b.v
 	b.v = nil
 	b.Clear()
+	b.UDP = nil
 	pool.Put(p)
 }
 
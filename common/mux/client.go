@@ -0,0 +1,402 @@
+package mux
+
+import (
+	"context"
+	"io"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/vmessocket/vmessocket/common"
+	"github.com/vmessocket/vmessocket/common/buf"
+	"github.com/vmessocket/vmessocket/common/errors"
+	"github.com/vmessocket/vmessocket/common/net"
+	"github.com/vmessocket/vmessocket/common/protocol"
+	"github.com/vmessocket/vmessocket/common/session"
+	"github.com/vmessocket/vmessocket/common/signal/done"
+	"github.com/vmessocket/vmessocket/common/task"
+	"github.com/vmessocket/vmessocket/proxy"
+	"github.com/vmessocket/vmessocket/transport"
+	"github.com/vmessocket/vmessocket/transport/internet"
+	"github.com/vmessocket/vmessocket/transport/pipe"
+)
+
+const defaultIdleTimeout = time.Second * 16
+
+type ClientManager struct {
+	Enabled bool
+	Picker  WorkerPicker
+}
+
+type ClientStrategy struct {
+	MaxConcurrency uint32
+	MaxConnection  uint32
+	IdleTimeout    time.Duration
+	MaxLifetime    time.Duration
+}
+
+type ClientWorker struct {
+	sessionManager *SessionManager
+	link           transport.Link
+	done           *done.Instance
+	strategy       ClientStrategy
+	created        time.Time
+}
+
+type ClientWorkerFactory interface {
+	Create() (*ClientWorker, error)
+}
+
+type DialingWorkerFactory struct {
+	Proxy    proxy.Outbound
+	Dialer   internet.Dialer
+	Strategy ClientStrategy
+}
+
+type IncrementalWorkerPicker struct {
+	Factory     ClientWorkerFactory
+	MaxIdle     uint32
+	access      sync.Mutex
+	workers     []*ClientWorker
+	cleanupTask *task.Periodic
+	closed      bool
+}
+
+type WorkerPicker interface {
+	PickAvailable() (*ClientWorker, error)
+}
+
+func fetchInput(ctx context.Context, s *Session, output buf.Writer) {
+	dest := session.OutboundFromContext(ctx).Target
+	transferType := protocol.TransferTypeStream
+	if dest.Network == net.Network_UDP {
+		transferType = protocol.TransferTypePacket
+	}
+	s.transferType = transferType
+	writer := NewWriter(s.ID, dest, output, transferType)
+	if transferType == protocol.TransferTypePacket {
+		writer.globalID = GlobalIDFromContext(ctx)
+	}
+	defer s.Close()
+	defer writer.Close()
+	newError("dispatching request to ", dest).WriteToLog(session.ExportIDToError(ctx))
+	if err := writeFirstPayload(s.input, writer); err != nil {
+		newError("failed to write first payload").Base(err).WriteToLog(session.ExportIDToError(ctx))
+		writer.hasError = true
+		common.Interrupt(s.input)
+		return
+	}
+	if err := buf.Copy(s.input, writer); err != nil {
+		newError("failed to fetch all input").Base(err).WriteToLog(session.ExportIDToError(ctx))
+		writer.hasError = true
+		common.Interrupt(s.input)
+		return
+	}
+}
+
+func NewClientWorker(stream transport.Link, s ClientStrategy) (*ClientWorker, error) {
+	c := &ClientWorker{
+		sessionManager: NewSessionManager(),
+		link:           stream,
+		done:           done.New(),
+		strategy:       s,
+		created:        time.Now(),
+	}
+	go c.fetchOutput()
+	go c.monitor()
+	return c, nil
+}
+
+func writeFirstPayload(reader buf.Reader, writer *Writer) error {
+	err := buf.CopyOnceTimeout(reader, writer, time.Millisecond*100)
+	if err == buf.ErrNotTimeoutReader || err == buf.ErrReadTimeout {
+		return writer.WriteMultiBuffer(buf.MultiBuffer{})
+	}
+	return err
+}
+
+func (m *ClientWorker) ActiveConnections() uint32 {
+	return uint32(m.sessionManager.Size())
+}
+
+func (p *IncrementalWorkerPicker) cleanup() {
+	var activeWorkers []*ClientWorker
+	idle := uint32(0)
+	for _, w := range p.workers {
+		if w.Closed() {
+			continue
+		}
+		if w.ActiveConnections() == 0 {
+			idle++
+			if p.MaxIdle > 0 && idle > p.MaxIdle && w.closeIfIdle() {
+				continue
+			}
+		}
+		activeWorkers = append(activeWorkers, w)
+	}
+	p.workers = activeWorkers
+}
+
+func (p *IncrementalWorkerPicker) cleanupFunc() error {
+	p.access.Lock()
+	defer p.access.Unlock()
+	if len(p.workers) == 0 {
+		return newError("no worker")
+	}
+	p.cleanup()
+	return nil
+}
+
+func (m *ClientManager) Close() error {
+	return common.Close(m.Picker)
+}
+
+func (p *IncrementalWorkerPicker) Close() error {
+	p.access.Lock()
+	defer p.access.Unlock()
+	p.closed = true
+	if p.cleanupTask != nil {
+		common.Must(p.cleanupTask.Close())
+	}
+	for _, w := range p.workers {
+		common.Must(w.done.Close())
+	}
+	p.workers = nil
+	return nil
+}
+
+func (m *ClientWorker) closeIfIdle() bool {
+	if !m.sessionManager.CloseIfNoSession() {
+		return false
+	}
+	common.Must(m.done.Close())
+	return true
+}
+
+func (m *ClientWorker) Closed() bool {
+	return m.done.Done()
+}
+
+func (f *DialingWorkerFactory) Create() (*ClientWorker, error) {
+	opts := []pipe.Option{pipe.WithSizeLimit(64 * 1024)}
+	uplinkReader, uplinkWriter := pipe.New(opts...)
+	downlinkReader, downlinkWriter := pipe.New(opts...)
+	c, err := NewClientWorker(transport.Link{
+		Reader: downlinkReader,
+		Writer: uplinkWriter,
+	}, f.Strategy)
+	if err != nil {
+		return nil, err
+	}
+	go func(p proxy.Outbound, d internet.Dialer, c common.Closable) {
+		ctx := session.ContextWithOutbound(context.Background(), &session.Outbound{
+			Target: net.TCPDestination(muxCoolAddress, muxCoolPort),
+		})
+		ctx, cancel := context.WithCancel(ctx)
+		if err := p.Process(ctx, &transport.Link{Reader: uplinkReader, Writer: downlinkWriter}, d); err != nil {
+			newError("failed to handler mux client connection").Base(err).WriteToLog()
+		}
+		common.Must(c.Close())
+		cancel()
+	}(f.Proxy, f.Dialer, c.done)
+	return c, nil
+}
+
+func (m *ClientManager) Dispatch(ctx context.Context, link *transport.Link) error {
+	for i := 0; i < 16; i++ {
+		worker, err := m.Picker.PickAvailable()
+		if err != nil {
+			return err
+		}
+		if worker.Dispatch(ctx, link) {
+			return nil
+		}
+	}
+	return newError("unable to find an available mux client").AtWarning()
+}
+
+func (m *ClientWorker) Dispatch(ctx context.Context, link *transport.Link) bool {
+	if m.IsFull() || m.Closed() {
+		return false
+	}
+	s := m.sessionManager.Allocate()
+	if s == nil {
+		return false
+	}
+	s.input = link.Reader
+	s.output = link.Writer
+	go fetchInput(ctx, s, m.link.Writer)
+	return true
+}
+
+func (m *ClientWorker) fetchOutput() {
+	defer func() {
+		common.Must(m.done.Close())
+	}()
+	reader := &buf.BufferedReader{Reader: m.link.Reader}
+	var meta FrameMetadata
+	for {
+		err := meta.Unmarshal(reader)
+		if err != nil {
+			if errors.Cause(err) != io.EOF {
+				newError("failed to read metadata").Base(err).WriteToLog()
+			}
+			break
+		}
+		switch meta.SessionStatus {
+		case SessionStatusKeepAlive:
+			err = m.handleStatusKeepAlive(&meta, reader)
+		case SessionStatusEnd:
+			err = m.handleStatusEnd(&meta, reader)
+		case SessionStatusNew:
+			err = m.handleStatusNew(&meta, reader)
+		case SessionStatusKeep:
+			err = m.handleStatusKeep(&meta, reader)
+		default:
+			newError("unknown status: ", meta.SessionStatus).AtError().WriteToLog()
+			return
+		}
+		if err != nil {
+			newError("failed to process data").Base(err).WriteToLog()
+			return
+		}
+	}
+}
+
+func (p *IncrementalWorkerPicker) findAvailable() int {
+	for idx, w := range p.workers {
+		if !w.IsFull() {
+			return idx
+		}
+	}
+	return -1
+}
+
+func (m *ClientWorker) handleStatusEnd(meta *FrameMetadata, reader *buf.BufferedReader) error {
+	if s, found := m.sessionManager.Get(meta.SessionID); found {
+		if meta.Option.Has(OptionError) {
+			common.Interrupt(s.input)
+			common.Interrupt(s.output)
+		}
+		s.Close()
+	}
+	if meta.Option.Has(OptionData) {
+		return buf.Copy(NewStreamReader(reader), buf.Discard)
+	}
+	return nil
+}
+
+func (m *ClientWorker) handleStatusKeep(meta *FrameMetadata, reader *buf.BufferedReader) error {
+	if !meta.Option.Has(OptionData) {
+		return nil
+	}
+	s, found := m.sessionManager.Get(meta.SessionID)
+	if !found {
+		closingWriter := NewResponseWriter(meta.SessionID, m.link.Writer, protocol.TransferTypeStream)
+		closingWriter.Close()
+		return buf.Copy(NewStreamReader(reader), buf.Discard)
+	}
+	rr := s.NewReader(reader, &meta.Target)
+	err := buf.Copy(rr, s.output)
+	if err != nil && buf.IsWriteError(err) {
+		newError("failed to write to downstream. closing session ", s.ID).Base(err).WriteToLog()
+		closingWriter := NewResponseWriter(meta.SessionID, m.link.Writer, protocol.TransferTypeStream)
+		closingWriter.Close()
+		drainErr := buf.Copy(rr, buf.Discard)
+		common.Interrupt(s.input)
+		s.Close()
+		return drainErr
+	}
+	return err
+}
+
+func (m *ClientWorker) handleStatusKeepAlive(meta *FrameMetadata, reader *buf.BufferedReader) error {
+	if meta.Option.Has(OptionData) {
+		return buf.Copy(NewStreamReader(reader), buf.Discard)
+	}
+	return nil
+}
+
+func (m *ClientWorker) handleStatusNew(meta *FrameMetadata, reader *buf.BufferedReader) error {
+	if meta.Option.Has(OptionData) {
+		return buf.Copy(NewStreamReader(reader), buf.Discard)
+	}
+	return nil
+}
+
+func (m *ClientWorker) IsClosing() bool {
+	if m.sessionManager.Count() >= math.MaxUint16 {
+		return true
+	}
+	if m.strategy.MaxConnection > 0 && m.sessionManager.Count() >= int(m.strategy.MaxConnection) {
+		return true
+	}
+	return m.strategy.MaxLifetime > 0 && time.Since(m.created) >= m.strategy.MaxLifetime
+}
+
+func (m *ClientWorker) IsFull() bool {
+	if m.IsClosing() || m.Closed() {
+		return true
+	}
+	return m.strategy.MaxConcurrency > 0 && m.sessionManager.Size() >= int(m.strategy.MaxConcurrency)
+}
+
+func (m *ClientWorker) monitor() {
+	idleTimeout := m.strategy.IdleTimeout
+	if idleTimeout <= 0 {
+		idleTimeout = defaultIdleTimeout
+	}
+	timer := time.NewTicker(time.Second)
+	defer timer.Stop()
+	for {
+		select {
+		case <-m.done.Wait():
+			m.sessionManager.Close()
+			common.Close(m.link.Writer)
+			common.Interrupt(m.link.Reader)
+			return
+		case <-timer.C:
+			idle := m.sessionManager.IdleTime()
+			if idle == 0 || (idle < idleTimeout && !m.IsClosing()) {
+				continue
+			}
+			m.closeIfIdle()
+		}
+	}
+}
+
+func (p *IncrementalWorkerPicker) PickAvailable() (*ClientWorker, error) {
+	worker, start, err := p.pickInternal()
+	if start {
+		common.Must(p.cleanupTask.Start())
+	}
+	return worker, err
+}
+
+func (p *IncrementalWorkerPicker) pickInternal() (*ClientWorker, bool, error) {
+	p.access.Lock()
+	defer p.access.Unlock()
+	if p.closed {
+		return nil, false, newError("worker picker is closed")
+	}
+	if idx := p.findAvailable(); idx >= 0 {
+		return p.workers[idx], false, nil
+	}
+	p.cleanup()
+	worker, err := p.Factory.Create()
+	if err != nil {
+		return nil, false, err
+	}
+	p.workers = append(p.workers, worker)
+	if p.cleanupTask == nil {
+		p.cleanupTask = &task.Periodic{
+			Interval: time.Second * 5,
+			Execute:  p.cleanupFunc,
+		}
+	}
+	return worker, true, nil
+}
+
+func (m *ClientWorker) TotalConnections() uint32 {
+	return uint32(m.sessionManager.Count())
+}
@@ -0,0 +1,196 @@
+package mux
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/vmessocket/vmessocket/common"
+	"github.com/vmessocket/vmessocket/common/net"
+	"github.com/vmessocket/vmessocket/common/session"
+	"github.com/vmessocket/vmessocket/transport"
+	"github.com/vmessocket/vmessocket/transport/pipe"
+)
+
+type pipeWorkerFactory struct {
+	strategy ClientStrategy
+	created  int
+}
+
+func dispatchSession(t *testing.T, worker *ClientWorker) (*pipe.Writer, bool) {
+	t.Helper()
+	ctx := session.ContextWithOutbound(context.Background(), &session.Outbound{
+		Target: net.TCPDestination(net.DomainAddress("example.com"), 80),
+	})
+	reader, writer := pipe.New()
+	_, downlinkWriter := pipe.New(pipe.DiscardOverflow())
+	return writer, worker.Dispatch(ctx, &transport.Link{Reader: reader, Writer: downlinkWriter})
+}
+
+func newPipeWorker(t *testing.T, strategy ClientStrategy) *ClientWorker {
+	t.Helper()
+	worker, err := (&pipeWorkerFactory{strategy: strategy}).Create()
+	common.Must(err)
+	t.Cleanup(func() {
+		worker.done.Close()
+	})
+	return worker
+}
+
+func waitFor(t *testing.T, timeout time.Duration, condition func() bool) bool {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if condition() {
+			return true
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	return condition()
+}
+
+func (f *pipeWorkerFactory) Create() (*ClientWorker, error) {
+	f.created++
+	_, uplinkWriter := pipe.New(pipe.DiscardOverflow())
+	downlinkReader, _ := pipe.New()
+	return NewClientWorker(transport.Link{Reader: downlinkReader, Writer: uplinkWriter}, f.strategy)
+}
+
+func TestClientWorkerMaxConcurrency(t *testing.T) {
+	worker := newPipeWorker(t, ClientStrategy{MaxConcurrency: 2})
+	first, ok := dispatchSession(t, worker)
+	if !ok {
+		t.Fatal("first session rejected")
+	}
+	if _, ok := dispatchSession(t, worker); !ok {
+		t.Fatal("second session rejected")
+	}
+	if !worker.IsFull() {
+		t.Fatal("worker with two sessions is not full")
+	}
+	if _, ok := dispatchSession(t, worker); ok {
+		t.Fatal("third session accepted above the concurrency cap")
+	}
+	common.Must(first.Close())
+	if !waitFor(t, time.Second, func() bool { return !worker.IsFull() }) {
+		t.Fatal("worker still full after a session ended")
+	}
+	if _, ok := dispatchSession(t, worker); !ok {
+		t.Fatal("session rejected after a slot was freed")
+	}
+}
+
+func TestClientWorkerMaxConnection(t *testing.T) {
+	worker := newPipeWorker(t, ClientStrategy{MaxConnection: 3})
+	for i := 0; i < 3; i++ {
+		writer, ok := dispatchSession(t, worker)
+		if !ok {
+			t.Fatal("session ", i, " rejected")
+		}
+		common.Must(writer.Close())
+	}
+	if !worker.IsClosing() {
+		t.Fatal("worker keeps accepting after MaxConnection sessions")
+	}
+	if _, ok := dispatchSession(t, worker); ok {
+		t.Fatal("session accepted by a retiring worker")
+	}
+	if !waitFor(t, 3*time.Second, worker.Closed) {
+		t.Fatal("retiring worker was not closed once its sessions ended")
+	}
+}
+
+func TestClientWorkerIdleTimeout(t *testing.T) {
+	worker := newPipeWorker(t, ClientStrategy{IdleTimeout: time.Second})
+	writer, ok := dispatchSession(t, worker)
+	if !ok {
+		t.Fatal("session rejected")
+	}
+	time.Sleep(1500 * time.Millisecond)
+	if worker.Closed() {
+		t.Fatal("worker with an active session was reaped")
+	}
+	common.Must(writer.Close())
+	if !waitFor(t, 3*time.Second, worker.Closed) {
+		t.Fatal("idle worker was not reaped")
+	}
+}
+
+func TestIncrementalWorkerPickerMaxIdle(t *testing.T) {
+	factory := &pipeWorkerFactory{strategy: ClientStrategy{MaxConcurrency: 1}}
+	picker := &IncrementalWorkerPicker{
+		Factory: factory,
+		MaxIdle: 1,
+	}
+	defer picker.Close()
+	var writers []*pipe.Writer
+	var workers []*ClientWorker
+	for i := 0; i < 3; i++ {
+		worker, err := picker.PickAvailable()
+		common.Must(err)
+		writer, ok := dispatchSession(t, worker)
+		if !ok {
+			t.Fatal("session rejected by a fresh worker")
+		}
+		writers = append(writers, writer)
+		workers = append(workers, worker)
+	}
+	if factory.created != 3 {
+		t.Fatal("expected one worker per session, got ", factory.created)
+	}
+	for _, writer := range writers {
+		common.Must(writer.Close())
+	}
+	if !waitFor(t, time.Second, func() bool {
+		for _, w := range workers {
+			if w.ActiveConnections() != 0 {
+				return false
+			}
+		}
+		return true
+	}) {
+		t.Fatal("sessions did not end")
+	}
+	common.Must(picker.cleanupFunc())
+	closed := 0
+	for _, w := range workers {
+		if w.Closed() {
+			closed++
+		}
+	}
+	if closed != 2 || len(picker.workers) != 1 {
+		t.Fatal("expected one idle worker to be kept, closed ", closed, ", kept ", len(picker.workers))
+	}
+}
+
+func TestIncrementalWorkerPickerClose(t *testing.T) {
+	picker := &IncrementalWorkerPicker{
+		Factory: &pipeWorkerFactory{},
+	}
+	worker, err := picker.PickAvailable()
+	common.Must(err)
+	common.Must(picker.Close())
+	if !worker.Closed() {
+		t.Fatal("worker is still open after the picker was closed")
+	}
+	if _, err := picker.PickAvailable(); err == nil {
+		t.Fatal("closed picker created a worker")
+	}
+}
+
+func TestSessionManagerAllocateStopsBeforeWrap(t *testing.T) {
+	m := NewSessionManager()
+	first := m.Allocate()
+	m.count = math.MaxUint16 - 1
+	last := m.Allocate()
+	if last == nil || last.ID != math.MaxUint16 {
+		t.Fatal("expected the last session ID to be allocated, got ", last)
+	}
+	if s := m.Allocate(); s != nil {
+		t.Fatal("allocated session ", s.ID, " after the ID space was exhausted")
+	}
+	if s, found := m.Get(first.ID); !found || s != first {
+		t.Fatal("live session was overwritten")
+	}
+}
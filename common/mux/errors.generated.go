@@ -0,0 +1,9 @@
+package mux
+
+import "github.com/vmessocket/vmessocket/common/errors"
+
+type errPathObjHolder struct{}
+
+func newError(values ...interface{}) *errors.Error {
+	return errors.New(values...).WithPathObj(errPathObjHolder{})
+}
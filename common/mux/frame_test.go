@@ -0,0 +1,136 @@
+package mux_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/vmessocket/vmessocket/common"
+	"github.com/vmessocket/vmessocket/common/buf"
+	. "github.com/vmessocket/vmessocket/common/mux"
+	"github.com/vmessocket/vmessocket/common/net"
+)
+
+func TestFrameMetadataRoundTrip(t *testing.T) {
+	testCases := []struct {
+		name string
+		meta FrameMetadata
+	}{
+		{
+			name: "new tcp",
+			meta: FrameMetadata{
+				SessionID:     1,
+				SessionStatus: SessionStatusNew,
+				Target:        net.TCPDestination(net.DomainAddress("example.com"), 443),
+			},
+		},
+		{
+			name: "new udp with global id",
+			meta: FrameMetadata{
+				SessionID:     2,
+				SessionStatus: SessionStatusNew,
+				Option:        OptionData,
+				Target:        net.UDPDestination(net.ParseAddress("8.8.8.8"), 53),
+				GlobalID:      [8]byte{1, 2, 3, 4, 5, 6, 7, 8},
+			},
+		},
+		{
+			name: "keep udp with address",
+			meta: FrameMetadata{
+				SessionID:     3,
+				SessionStatus: SessionStatusKeep,
+				Option:        OptionData,
+				Target:        net.UDPDestination(net.ParseAddress("2001:db8::1"), 5353),
+			},
+		},
+		{
+			name: "keep without address",
+			meta: FrameMetadata{
+				SessionID:     4,
+				SessionStatus: SessionStatusKeep,
+				Option:        OptionData,
+			},
+		},
+		{
+			name: "end with error",
+			meta: FrameMetadata{
+				SessionID:     5,
+				SessionStatus: SessionStatusEnd,
+				Option:        OptionError,
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			b := buf.New()
+			defer b.Release()
+			common.Must(tc.meta.WriteTo(b))
+			var meta FrameMetadata
+			if err := meta.Unmarshal(bytes.NewReader(b.Bytes())); err != nil {
+				t.Fatal(err)
+			}
+			if meta != tc.meta {
+				t.Fatalf("expected %+v, got %+v", tc.meta, meta)
+			}
+		})
+	}
+}
+
+func TestFrameMetadataUnmarshalMalformed(t *testing.T) {
+	testCases := []struct {
+		name  string
+		input []byte
+	}{
+		{
+			name:  "empty",
+			input: []byte{},
+		},
+		{
+			name:  "truncated length",
+			input: []byte{0x00},
+		},
+		{
+			name:  "oversized metadata",
+			input: []byte{0x02, 0x01, 0x00, 0x01},
+		},
+		{
+			name:  "truncated metadata",
+			input: []byte{0x00, 0x08, 0x00, 0x01, 0x01},
+		},
+		{
+			name:  "short header",
+			input: []byte{0x00, 0x03, 0x00, 0x01, 0x01},
+		},
+		{
+			name:  "new without target",
+			input: []byte{0x00, 0x04, 0x00, 0x01, 0x01, 0x00},
+		},
+		{
+			name:  "unknown network",
+			input: []byte{0x00, 0x0c, 0x00, 0x01, 0x01, 0x00, 0x03, 0x00, 0x35, 0x01, 0x08, 0x08, 0x08, 0x08},
+		},
+		{
+			name:  "unknown address type",
+			input: []byte{0x00, 0x0c, 0x00, 0x01, 0x01, 0x00, 0x02, 0x00, 0x35, 0x09, 0x08, 0x08, 0x08, 0x08},
+		},
+		{
+			name:  "truncated ipv6",
+			input: []byte{0x00, 0x0c, 0x00, 0x01, 0x01, 0x00, 0x02, 0x00, 0x35, 0x03, 0x20, 0x01, 0x0d, 0xb8},
+		},
+		{
+			name:  "truncated domain",
+			input: []byte{0x00, 0x0a, 0x00, 0x01, 0x01, 0x00, 0x01, 0x01, 0xbb, 0x02, 0x10, 0x61},
+		},
+		{
+			name:  "keep with partial target",
+			input: []byte{0x00, 0x06, 0x00, 0x01, 0x02, 0x01, 0x02, 0x00},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var meta FrameMetadata
+			if err := meta.Unmarshal(bytes.NewReader(tc.input)); err == nil {
+				t.Fatalf("expected error, got %+v", meta)
+			}
+		})
+	}
+}
@@ -2,11 +2,19 @@ package mux
 
 import (
 	"context"
+	"io"
 
+	"github.com/vmessocket/vmessocket/common"
+	"github.com/vmessocket/vmessocket/common/buf"
+	"github.com/vmessocket/vmessocket/common/errors"
+	"github.com/vmessocket/vmessocket/common/log"
 	"github.com/vmessocket/vmessocket/common/net"
+	"github.com/vmessocket/vmessocket/common/protocol"
+	"github.com/vmessocket/vmessocket/common/session"
 	"github.com/vmessocket/vmessocket/core"
 	"github.com/vmessocket/vmessocket/features/routing"
 	"github.com/vmessocket/vmessocket/transport"
+	"github.com/vmessocket/vmessocket/transport/pipe"
 )
 
 type Server struct {
@@ -19,6 +27,16 @@ type ServerWorker struct {
 	sessionManager *SessionManager
 }
 
+func handle(ctx context.Context, s *Session, output buf.Writer) {
+	writer := NewResponseWriter(s.ID, output, s.transferType)
+	if err := buf.Copy(s.input, writer); err != nil {
+		newError("session ", s.ID, " ends.").Base(err).WriteToLog(session.ExportIDToError(ctx))
+		writer.hasError = true
+	}
+	writer.Close()
+	s.Close()
+}
+
 func NewServer(ctx context.Context) *Server {
 	s := &Server{}
 	core.RequireFeatures(ctx, func(d routing.Dispatcher) {
@@ -33,15 +51,213 @@ func NewServerWorker(ctx context.Context, d routing.Dispatcher, link *transport.
 		link:           link,
 		sessionManager: NewSessionManager(),
 	}
+	go worker.run(ctx)
 	return worker, nil
 }
 
+func (w *ServerWorker) ActiveConnections() uint32 {
+	return uint32(w.sessionManager.Size())
+}
+
 func (s *Server) Close() error {
 	return nil
 }
 
+func (w *ServerWorker) Closed() bool {
+	return w.sessionManager.Closed()
+}
+
 func (s *Server) Dispatch(ctx context.Context, dest net.Destination) (*transport.Link, error) {
-	return s.dispatcher.Dispatch(ctx, dest)
+	if dest.Address != muxCoolAddress {
+		return s.dispatcher.Dispatch(ctx, dest)
+	}
+	opts := pipe.OptionsFromContext(ctx)
+	uplinkReader, uplinkWriter := pipe.New(opts...)
+	downlinkReader, downlinkWriter := pipe.New(opts...)
+	if _, err := NewServerWorker(ctx, s.dispatcher, &transport.Link{
+		Reader: uplinkReader,
+		Writer: downlinkWriter,
+	}); err != nil {
+		return nil, err
+	}
+	return &transport.Link{Reader: downlinkReader, Writer: uplinkWriter}, nil
+}
+
+func (w *ServerWorker) handleFrame(ctx context.Context, reader *buf.BufferedReader) error {
+	var meta FrameMetadata
+	err := meta.Unmarshal(reader)
+	if err != nil {
+		return newError("failed to read metadata").Base(err)
+	}
+	switch meta.SessionStatus {
+	case SessionStatusKeepAlive:
+		err = w.handleStatusKeepAlive(&meta, reader)
+	case SessionStatusEnd:
+		err = w.handleStatusEnd(&meta, reader)
+	case SessionStatusNew:
+		err = w.handleStatusNew(ctx, &meta, reader)
+	case SessionStatusKeep:
+		err = w.handleStatusKeep(&meta, reader)
+	default:
+		return newError("unknown status: ", meta.SessionStatus).AtError()
+	}
+	if err != nil {
+		return newError("failed to process data").Base(err)
+	}
+	return nil
+}
+
+func (w *ServerWorker) handleGlobalSession(ctx context.Context, meta *FrameMetadata, reader *buf.BufferedReader) error {
+	gs, created, err := globalSessions.GetOrCreate(ctx, meta.GlobalID, func() (*transport.Link, error) {
+		return w.dispatcher.Dispatch(detachedContext(ctx), meta.Target)
+	})
+	if err != nil {
+		if meta.Option.Has(OptionData) {
+			buf.Copy(NewStreamReader(reader), buf.Discard)
+		}
+		return newError("failed to dispatch request.").Base(err)
+	}
+	if !created {
+		newError("resuming global session for ", meta.Target).AtDebug().WriteToLog(session.ExportIDToError(ctx))
+	}
+	s := &Session{
+		output:       gs.link.Writer,
+		parent:       w.sessionManager,
+		ID:           meta.SessionID,
+		transferType: protocol.TransferTypePacket,
+		global:       gs,
+	}
+	w.sessionManager.Add(s)
+	gs.attach(s, NewResponseWriter(meta.SessionID, w.link.Writer, protocol.TransferTypePacket))
+	if !meta.Option.Has(OptionData) {
+		return nil
+	}
+	rr := s.NewReader(reader, &meta.Target)
+	if err := buf.Copy(rr, s.output); err != nil {
+		buf.Copy(rr, buf.Discard)
+		return s.Close()
+	}
+	return nil
+}
+
+func (w *ServerWorker) handleStatusEnd(meta *FrameMetadata, reader *buf.BufferedReader) error {
+	if s, found := w.sessionManager.Get(meta.SessionID); found {
+		if meta.Option.Has(OptionError) && s.global == nil {
+			common.Interrupt(s.input)
+			common.Interrupt(s.output)
+		}
+		s.Close()
+	}
+	if meta.Option.Has(OptionData) {
+		return buf.Copy(NewStreamReader(reader), buf.Discard)
+	}
+	return nil
+}
+
+func (w *ServerWorker) handleStatusKeep(meta *FrameMetadata, reader *buf.BufferedReader) error {
+	if !meta.Option.Has(OptionData) {
+		return nil
+	}
+	s, found := w.sessionManager.Get(meta.SessionID)
+	if !found {
+		closingWriter := NewResponseWriter(meta.SessionID, w.link.Writer, protocol.TransferTypeStream)
+		closingWriter.Close()
+		return buf.Copy(NewStreamReader(reader), buf.Discard)
+	}
+	rr := s.NewReader(reader, &meta.Target)
+	err := buf.Copy(rr, s.output)
+	if err != nil && buf.IsWriteError(err) {
+		newError("failed to write to downstream writer. closing session ", s.ID).Base(err).WriteToLog()
+		closingWriter := NewResponseWriter(meta.SessionID, w.link.Writer, protocol.TransferTypeStream)
+		closingWriter.Close()
+		drainErr := buf.Copy(rr, buf.Discard)
+		common.Interrupt(s.input)
+		s.Close()
+		return drainErr
+	}
+	return err
+}
+
+func (w *ServerWorker) handleStatusKeepAlive(meta *FrameMetadata, reader *buf.BufferedReader) error {
+	if meta.Option.Has(OptionData) {
+		return buf.Copy(NewStreamReader(reader), buf.Discard)
+	}
+	return nil
+}
+
+func (w *ServerWorker) handleStatusNew(ctx context.Context, meta *FrameMetadata, reader *buf.BufferedReader) error {
+	newError("received request for ", meta.Target).WriteToLog(session.ExportIDToError(ctx))
+	{
+		msg := &log.AccessMessage{
+			To:     meta.Target,
+			Status: log.AccessAccepted,
+			Reason: "",
+		}
+		if inbound := session.InboundFromContext(ctx); inbound != nil && inbound.Source.IsValid() {
+			msg.From = inbound.Source
+			if inbound.User != nil {
+				msg.Email = inbound.User.Email
+			}
+		}
+		ctx = log.ContextWithAccessMessage(ctx, msg)
+	}
+	if meta.Target.Network == net.Network_UDP && meta.GlobalID != [8]byte{} {
+		if _, _, ok := globalSessionOwner(ctx); ok {
+			return w.handleGlobalSession(ctx, meta, reader)
+		}
+	}
+	link, err := w.dispatcher.Dispatch(ctx, meta.Target)
+	if err != nil {
+		if meta.Option.Has(OptionData) {
+			buf.Copy(NewStreamReader(reader), buf.Discard)
+		}
+		return newError("failed to dispatch request.").Base(err)
+	}
+	s := &Session{
+		input:        link.Reader,
+		output:       link.Writer,
+		parent:       w.sessionManager,
+		ID:           meta.SessionID,
+		transferType: protocol.TransferTypeStream,
+	}
+	if meta.Target.Network == net.Network_UDP {
+		s.transferType = protocol.TransferTypePacket
+	}
+	w.sessionManager.Add(s)
+	go handle(ctx, s, w.link.Writer)
+	if !meta.Option.Has(OptionData) {
+		return nil
+	}
+	rr := s.NewReader(reader, &meta.Target)
+	if err := buf.Copy(rr, s.output); err != nil {
+		buf.Copy(rr, buf.Discard)
+		common.Interrupt(s.input)
+		return s.Close()
+	}
+	return nil
+}
+
+func (w *ServerWorker) run(ctx context.Context) {
+	input := w.link.Reader
+	reader := &buf.BufferedReader{Reader: input}
+	defer func() {
+		w.sessionManager.Close()
+		common.Close(w.link.Writer)
+	}()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+			if err := w.handleFrame(ctx, reader); err != nil {
+				if errors.Cause(err) != io.EOF {
+					newError("unexpected EOF").Base(err).WriteToLog(session.ExportIDToError(ctx))
+					common.Interrupt(input)
+				}
+				return
+			}
+		}
+	}
 }
 
 func (s *Server) Start() error {
@@ -0,0 +1,48 @@
+package mux
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/vmessocket/vmessocket/common"
+	"github.com/vmessocket/vmessocket/common/buf"
+	"github.com/vmessocket/vmessocket/common/net"
+	"github.com/vmessocket/vmessocket/common/protocol"
+	"github.com/vmessocket/vmessocket/transport"
+	"github.com/vmessocket/vmessocket/transport/pipe"
+)
+
+func TestServerWorkerSessions(t *testing.T) {
+	dispatcher := newTestDispatcher()
+	uplinkReader, uplinkWriter := pipe.New()
+	_, downlinkWriter := pipe.New(pipe.DiscardOverflow())
+	worker, err := NewServerWorker(context.Background(), dispatcher, &transport.Link{
+		Reader: uplinkReader,
+		Writer: downlinkWriter,
+	})
+	common.Must(err)
+	target := net.TCPDestination(net.DomainAddress("example.com"), 80)
+	var writers []*Writer
+	for id := uint16(1); id <= 2; id++ {
+		writer := NewWriter(id, target, uplinkWriter, protocol.TransferTypeStream)
+		b := buf.New()
+		common.Must2(b.WriteString("hello"))
+		common.Must(writer.WriteMultiBuffer(buf.MultiBuffer{b}))
+		if mb, err := dispatcher.remote(t).Reader.(*pipe.Reader).ReadMultiBufferTimeout(2 * time.Second); err != nil || mb.String() != "hello" {
+			t.Fatal("unexpected payload for session ", id, ": ", mb.String(), err)
+		}
+		writers = append(writers, writer)
+	}
+	if n := worker.ActiveConnections(); n != 2 {
+		t.Fatal("expected 2 active sessions, got ", n)
+	}
+	common.Must(writers[0].Close())
+	if !waitFor(t, time.Second, func() bool { return worker.ActiveConnections() == 1 }) {
+		t.Fatal("ended session was not removed")
+	}
+	common.Must(uplinkWriter.Close())
+	if !waitFor(t, time.Second, worker.Closed) {
+		t.Fatal("worker was not closed with its link")
+	}
+}
@@ -1,15 +1,162 @@
 package mux
 
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/vmessocket/vmessocket/common"
+	"github.com/vmessocket/vmessocket/common/buf"
+	"github.com/vmessocket/vmessocket/common/net"
+	"github.com/vmessocket/vmessocket/common/protocol"
+)
+
 type Session struct {
-	ID uint16
+	input        buf.Reader
+	output       buf.Writer
+	parent       *SessionManager
+	ID           uint16
+	transferType protocol.TransferType
+	global       *globalSession
 }
 
 type SessionManager struct {
-	sessions map[uint16]*Session
+	sync.RWMutex
+	sessions   map[uint16]*Session
+	count      uint16
+	closed     bool
+	lastActive time.Time
 }
 
 func NewSessionManager() *SessionManager {
 	return &SessionManager{
-		sessions: make(map[uint16]*Session, 16),
+		sessions:   make(map[uint16]*Session, 16),
+		lastActive: time.Now(),
+	}
+}
+
+func (m *SessionManager) Add(s *Session) {
+	m.Lock()
+	defer m.Unlock()
+	if m.closed {
+		return
 	}
+	m.count++
+	m.sessions[s.ID] = s
+	m.lastActive = time.Now()
+}
+
+func (m *SessionManager) Allocate() *Session {
+	m.Lock()
+	defer m.Unlock()
+	if m.closed || m.count == math.MaxUint16 {
+		return nil
+	}
+	m.count++
+	m.lastActive = time.Now()
+	s := &Session{
+		ID:     m.count,
+		parent: m,
+	}
+	m.sessions[s.ID] = s
+	return s
+}
+
+func (s *Session) Close() error {
+	s.closeLinks()
+	s.parent.Remove(s.ID)
+	return nil
+}
+
+func (m *SessionManager) Close() error {
+	m.Lock()
+	defer m.Unlock()
+	if m.closed {
+		return nil
+	}
+	m.closed = true
+	for _, s := range m.sessions {
+		s.closeLinks()
+	}
+	m.sessions = nil
+	return nil
+}
+
+func (m *SessionManager) CloseIfNoSession() bool {
+	m.Lock()
+	defer m.Unlock()
+	if m.closed {
+		return true
+	}
+	if len(m.sessions) != 0 {
+		return false
+	}
+	m.closed = true
+	return true
+}
+
+func (m *SessionManager) Closed() bool {
+	m.RLock()
+	defer m.RUnlock()
+	return m.closed
+}
+
+func (s *Session) closeLinks() {
+	if s.global != nil {
+		s.global.detach(s)
+		return
+	}
+	common.Close(s.output)
+	common.Close(s.input)
+}
+
+func (m *SessionManager) Count() int {
+	m.RLock()
+	defer m.RUnlock()
+	return int(m.count)
+}
+
+func (m *SessionManager) Get(id uint16) (*Session, bool) {
+	m.RLock()
+	defer m.RUnlock()
+	if m.closed {
+		return nil, false
+	}
+	s, found := m.sessions[id]
+	return s, found
+}
+
+func (m *SessionManager) IdleTime() time.Duration {
+	m.RLock()
+	defer m.RUnlock()
+	if len(m.sessions) != 0 {
+		return 0
+	}
+	return time.Since(m.lastActive)
+}
+
+func (s *Session) NewReader(reader *buf.BufferedReader, dest *net.Destination) buf.Reader {
+	if s.transferType == protocol.TransferTypeStream {
+		return NewStreamReader(reader)
+	}
+	return NewPacketReader(reader, dest)
+}
+
+func (m *SessionManager) Remove(id uint16) {
+	m.Lock()
+	defer m.Unlock()
+	if m.closed {
+		return
+	}
+	delete(m.sessions, id)
+	m.lastActive = time.Now()
+	if len(m.sessions) == 0 {
+		m.sessions = make(map[uint16]*Session, 16)
+	}
+}
+
+func (m *SessionManager) Size() int {
+	m.RLock()
+	defer m.RUnlock()
+	return len(m.sessions)
 }
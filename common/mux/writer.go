@@ -0,0 +1,125 @@
+package mux
+
+import (
+	"github.com/vmessocket/vmessocket/common"
+	"github.com/vmessocket/vmessocket/common/buf"
+	"github.com/vmessocket/vmessocket/common/net"
+	"github.com/vmessocket/vmessocket/common/protocol"
+	"github.com/vmessocket/vmessocket/common/serial"
+)
+
+type Writer struct {
+	dest         net.Destination
+	writer       buf.Writer
+	id           uint16
+	followup     bool
+	hasError     bool
+	transferType protocol.TransferType
+	globalID     [8]byte
+}
+
+func NewResponseWriter(id uint16, writer buf.Writer, transferType protocol.TransferType) *Writer {
+	return &Writer{
+		id:           id,
+		writer:       writer,
+		followup:     true,
+		transferType: transferType,
+	}
+}
+
+func NewWriter(id uint16, dest net.Destination, writer buf.Writer, transferType protocol.TransferType) *Writer {
+	return &Writer{
+		id:           id,
+		dest:         dest,
+		writer:       writer,
+		followup:     false,
+		transferType: transferType,
+	}
+}
+
+func writeMetaWithFrame(writer buf.Writer, meta FrameMetadata, data buf.MultiBuffer) error {
+	frame := buf.New()
+	if err := meta.WriteTo(frame); err != nil {
+		frame.Release()
+		return err
+	}
+	if _, err := serial.WriteUint16(frame, uint16(data.Len())); err != nil {
+		frame.Release()
+		return err
+	}
+	mb := make(buf.MultiBuffer, 0, len(data)+1)
+	mb = append(mb, frame)
+	mb = append(mb, data...)
+	return writer.WriteMultiBuffer(mb)
+}
+
+func (w *Writer) Close() error {
+	meta := FrameMetadata{
+		SessionID:     w.id,
+		SessionStatus: SessionStatusEnd,
+	}
+	if w.hasError {
+		meta.Option.Set(OptionError)
+	}
+	frame := buf.New()
+	common.Must(meta.WriteTo(frame))
+	w.writer.WriteMultiBuffer(buf.MultiBuffer{frame})
+	return nil
+}
+
+func (w *Writer) getNextFrameMeta() FrameMetadata {
+	meta := FrameMetadata{
+		SessionID: w.id,
+		Target:    w.dest,
+		GlobalID:  w.globalID,
+	}
+	if w.followup {
+		meta.SessionStatus = SessionStatusKeep
+	} else {
+		w.followup = true
+		meta.SessionStatus = SessionStatusNew
+	}
+	return meta
+}
+
+func (w *Writer) writeData(mb buf.MultiBuffer) error {
+	meta := w.getNextFrameMeta()
+	meta.Option.Set(OptionData)
+	if w.transferType == protocol.TransferTypePacket && len(mb) == 1 {
+		if dest, ok := net.PacketDestination(mb[0]); ok {
+			meta.Target = dest
+		}
+	}
+	return writeMetaWithFrame(w.writer, meta, mb)
+}
+
+func (w *Writer) writeMetaOnly() error {
+	meta := w.getNextFrameMeta()
+	b := buf.New()
+	if err := meta.WriteTo(b); err != nil {
+		b.Release()
+		return err
+	}
+	return w.writer.WriteMultiBuffer(buf.MultiBuffer{b})
+}
+
+func (w *Writer) WriteMultiBuffer(mb buf.MultiBuffer) error {
+	defer buf.ReleaseMulti(mb)
+	if mb.IsEmpty() {
+		return w.writeMetaOnly()
+	}
+	for !mb.IsEmpty() {
+		var chunk buf.MultiBuffer
+		if w.transferType == protocol.TransferTypeStream {
+			mb, chunk = buf.SplitSize(mb, 8*1024)
+		} else {
+			var b *buf.Buffer
+			mb, b = buf.SplitFirst(mb)
+			chunk = buf.MultiBuffer{b}
+		}
+		if err := w.writeData(chunk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
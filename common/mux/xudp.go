@@ -0,0 +1,214 @@
+package mux
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vmessocket/vmessocket/common"
+	"github.com/vmessocket/vmessocket/common/buf"
+	"github.com/vmessocket/vmessocket/common/protocol"
+	"github.com/vmessocket/vmessocket/common/session"
+	"github.com/vmessocket/vmessocket/common/task"
+	"github.com/vmessocket/vmessocket/transport"
+)
+
+const globalSessionTimeout = time.Minute
+
+var (
+	globalIDKey    [32]byte
+	globalSessions = &globalSessionManager{
+		sessions: make(map[globalSessionKey]*globalSession),
+	}
+)
+
+type globalSession struct {
+	sync.Mutex
+	key     globalSessionKey
+	owner   *protocol.MemoryUser
+	link    *transport.Link
+	session *Session
+	writer  *Writer
+	expire  time.Time
+}
+
+type globalSessionKey struct {
+	owner string
+	id    [8]byte
+}
+
+type globalSessionManager struct {
+	sync.Mutex
+	sessions map[globalSessionKey]*globalSession
+	cleanup  *task.Periodic
+}
+
+func detachedContext(ctx context.Context) context.Context {
+	detached := session.ContextWithID(context.Background(), session.IDFromContext(ctx))
+	if inbound := session.InboundFromContext(ctx); inbound != nil {
+		detached = session.ContextWithInbound(detached, inbound)
+	}
+	if content := session.ContentFromContext(ctx); content != nil {
+		detached = session.ContextWithContent(detached, content)
+	}
+	return detached
+}
+
+func GlobalIDFromContext(ctx context.Context) [8]byte {
+	var id [8]byte
+	inbound := session.InboundFromContext(ctx)
+	if inbound == nil || !inbound.Source.IsValid() || inbound.Source.Address == nil {
+		return id
+	}
+	h := hmac.New(sha256.New, globalIDKey[:])
+	h.Write([]byte(inbound.Source.String()))
+	copy(id[:], h.Sum(nil))
+	return id
+}
+
+func globalSessionOwner(ctx context.Context) (string, *protocol.MemoryUser, bool) {
+	inbound := session.InboundFromContext(ctx)
+	if inbound == nil || inbound.User == nil {
+		return "", nil, false
+	}
+	return inbound.Tag + "\x00" + strings.ToLower(inbound.User.Email), inbound.User, true
+}
+
+func (s *globalSession) attach(session *Session, writer *Writer) {
+	s.Lock()
+	previous, previousWriter := s.session, s.writer
+	s.session = session
+	s.writer = writer
+	s.expire = time.Time{}
+	s.Unlock()
+	if previous == nil || previous == session {
+		return
+	}
+	newError("global session ", previous.ID, " is taken over by session ", session.ID).AtDebug().WriteToLog()
+	previousWriter.Close()
+	previous.parent.Remove(previous.ID)
+}
+
+func (m *globalSessionManager) cleanupFunc() error {
+	m.Lock()
+	defer m.Unlock()
+	if len(m.sessions) == 0 {
+		return newError("no global session")
+	}
+	now := time.Now()
+	for id, s := range m.sessions {
+		if s.expired(now) {
+			delete(m.sessions, id)
+			common.Interrupt(s.link.Writer)
+			common.Interrupt(s.link.Reader)
+		}
+	}
+	return nil
+}
+
+func (s *globalSession) detach(session *Session) {
+	s.Lock()
+	defer s.Unlock()
+	if s.session != session {
+		return
+	}
+	s.session = nil
+	s.writer = nil
+	s.expire = time.Now().Add(globalSessionTimeout)
+}
+
+func (s *globalSession) expired(now time.Time) bool {
+	s.Lock()
+	defer s.Unlock()
+	return s.session == nil && !s.expire.IsZero() && now.After(s.expire)
+}
+
+func (m *globalSessionManager) GetOrCreate(ctx context.Context, id [8]byte, create func() (*transport.Link, error)) (*globalSession, bool, error) {
+	owner, user, ok := globalSessionOwner(ctx)
+	if !ok {
+		return nil, false, newError("global session requires an authenticated user")
+	}
+	s, created, err := m.getOrCreateInternal(globalSessionKey{owner: owner, id: id}, user, create)
+	if created {
+		common.Must(m.cleanup.Start())
+	}
+	return s, created, err
+}
+
+func (m *globalSessionManager) getOrCreateInternal(key globalSessionKey, owner *protocol.MemoryUser, create func() (*transport.Link, error)) (*globalSession, bool, error) {
+	m.Lock()
+	defer m.Unlock()
+	if s, found := m.sessions[key]; found {
+		if s.owner.Account != nil && !s.owner.Account.Equals(owner.Account) {
+			return nil, false, newError("global session belongs to another user")
+		}
+		return s, false, nil
+	}
+	link, err := create()
+	if err != nil {
+		return nil, false, err
+	}
+	s := &globalSession{
+		key:   key,
+		owner: owner,
+		link:  link,
+	}
+	m.sessions[key] = s
+	if m.cleanup == nil {
+		m.cleanup = &task.Periodic{
+			Interval: time.Second * 30,
+			Execute:  m.cleanupFunc,
+		}
+	}
+	go s.run()
+	return s, true, nil
+}
+
+func (m *globalSessionManager) Remove(s *globalSession) {
+	m.Lock()
+	defer m.Unlock()
+	if m.sessions[s.key] == s {
+		delete(m.sessions, s.key)
+	}
+}
+
+func (s *globalSession) run() {
+	for {
+		mb, err := s.link.Reader.ReadMultiBuffer()
+		if err != nil {
+			break
+		}
+		s.Lock()
+		writer := s.writer
+		s.Unlock()
+		if writer == nil {
+			buf.ReleaseMulti(mb)
+			continue
+		}
+		if err := writer.WriteMultiBuffer(mb); err != nil {
+			newError("failed to write response of global session").Base(err).AtDebug().WriteToLog()
+		}
+	}
+	globalSessions.Remove(s)
+	common.Interrupt(s.link.Writer)
+	s.Lock()
+	session := s.session
+	writer := s.writer
+	s.session = nil
+	s.writer = nil
+	s.Unlock()
+	if writer != nil {
+		writer.Close()
+	}
+	if session != nil {
+		session.parent.Remove(session.ID)
+	}
+}
+
+func init() {
+	common.Must2(rand.Read(globalIDKey[:]))
+}
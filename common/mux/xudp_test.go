@@ -0,0 +1,338 @@
+package mux
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/vmessocket/vmessocket/common"
+	"github.com/vmessocket/vmessocket/common/buf"
+	"github.com/vmessocket/vmessocket/common/net"
+	"github.com/vmessocket/vmessocket/common/protocol"
+	"github.com/vmessocket/vmessocket/common/session"
+	"github.com/vmessocket/vmessocket/transport"
+	"github.com/vmessocket/vmessocket/transport/pipe"
+)
+
+type testAccount string
+
+type testDispatcher struct {
+	dispatched int32
+	remotes    chan *transport.Link
+}
+
+func newTestDispatcher() *testDispatcher {
+	return &testDispatcher{
+		remotes: make(chan *transport.Link, 4),
+	}
+}
+
+func newTestContext(user *protocol.MemoryUser, source net.Destination, target net.Destination) context.Context {
+	ctx := session.ContextWithInbound(context.Background(), &session.Inbound{
+		Source: source,
+		Tag:    "in",
+		User:   user,
+	})
+	return session.ContextWithOutbound(ctx, &session.Outbound{
+		Target: target,
+	})
+}
+
+func readPacket(t *testing.T, reader buf.Reader) (string, net.Destination) {
+	t.Helper()
+	mb, err := reader.(buf.TimeoutReader).ReadMultiBufferTimeout(2 * time.Second)
+	if err != nil {
+		t.Fatal("failed to read packet: ", err)
+	}
+	defer buf.ReleaseMulti(mb)
+	dest, _ := net.PacketDestination(mb[0])
+	return mb.String(), dest
+}
+
+func removeGlobalSessions(id [8]byte) {
+	globalSessions.Lock()
+	defer globalSessions.Unlock()
+	for key, s := range globalSessions.sessions {
+		if key.id == id {
+			delete(globalSessions.sessions, key)
+			common.Interrupt(s.link.Reader)
+		}
+	}
+}
+
+func (a testAccount) Equals(account protocol.Account) bool {
+	other, ok := account.(testAccount)
+	return ok && other == a
+}
+
+func (d *testDispatcher) Close() error {
+	return nil
+}
+
+func (d *testDispatcher) Dispatch(ctx context.Context, dest net.Destination) (*transport.Link, error) {
+	atomic.AddInt32(&d.dispatched, 1)
+	uplinkReader, uplinkWriter := pipe.New()
+	downlinkReader, downlinkWriter := pipe.New()
+	d.remotes <- &transport.Link{Reader: uplinkReader, Writer: downlinkWriter}
+	return &transport.Link{Reader: downlinkReader, Writer: uplinkWriter}, nil
+}
+
+func (d *testDispatcher) remote(t *testing.T) *transport.Link {
+	t.Helper()
+	select {
+	case link := <-d.remotes:
+		return link
+	case <-time.After(2 * time.Second):
+		t.Fatal("request was not dispatched")
+		return nil
+	}
+}
+
+func (d *testDispatcher) Start() error {
+	return nil
+}
+
+func (d *testDispatcher) Type() interface{} {
+	return nil
+}
+
+func TestGlobalIDFromContext(t *testing.T) {
+	alice := &protocol.MemoryUser{Email: "alice@example.com", Account: testAccount("alice")}
+	bob := &protocol.MemoryUser{Email: "bob@example.com", Account: testAccount("bob")}
+	source := net.UDPDestination(net.ParseAddress("10.0.0.1"), 40000)
+	target := net.UDPDestination(net.ParseAddress("8.8.8.8"), 53)
+	base := GlobalIDFromContext(newTestContext(nil, source, target))
+	if base == ([8]byte{}) {
+		t.Fatal("expected a global ID for a UDP flow")
+	}
+	testCases := []struct {
+		name  string
+		ctx   context.Context
+		same  bool
+		empty bool
+	}{
+		{
+			name: "other target",
+			ctx:  newTestContext(nil, source, net.UDPDestination(net.ParseAddress("1.1.1.1"), 53)),
+			same: true,
+		},
+		{
+			name: "same flow with a user",
+			ctx:  newTestContext(alice, source, target),
+			same: true,
+		},
+		{
+			name: "other client port",
+			ctx:  newTestContext(nil, net.UDPDestination(net.ParseAddress("10.0.0.1"), 40001), target),
+		},
+		{
+			name: "other client port of the same user",
+			ctx:  newTestContext(bob, net.UDPDestination(net.ParseAddress("10.0.0.1"), 40001), target),
+		},
+		{
+			name: "other client address",
+			ctx:  newTestContext(nil, net.UDPDestination(net.ParseAddress("10.0.0.2"), 40000), target),
+		},
+		{
+			name: "tcp source",
+			ctx:  newTestContext(nil, net.TCPDestination(net.ParseAddress("10.0.0.1"), 40000), target),
+		},
+		{
+			name:  "no source",
+			ctx:   newTestContext(nil, net.Destination{}, target),
+			empty: true,
+		},
+		{
+			name:  "no inbound",
+			ctx:   context.Background(),
+			empty: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			id := GlobalIDFromContext(tc.ctx)
+			switch {
+			case tc.empty:
+				if id != ([8]byte{}) {
+					t.Fatal("expected empty global ID, got ", id)
+				}
+			case tc.same:
+				if id != base {
+					t.Fatal("expected same global ID")
+				}
+			default:
+				if id == base || id == ([8]byte{}) {
+					t.Fatal("expected a different global ID, got ", id)
+				}
+			}
+		})
+	}
+}
+
+func TestGlobalSessionSurvivesReconnect(t *testing.T) {
+	user := &protocol.MemoryUser{Email: "reconnect@example.com", Account: testAccount("reconnect")}
+	target := net.UDPDestination(net.ParseAddress("8.8.4.4"), 53)
+	globalID := GlobalIDFromContext(newTestContext(nil, net.UDPDestination(net.ParseAddress("192.168.1.2"), 5000), target))
+	defer removeGlobalSessions(globalID)
+	dispatcher := newTestDispatcher()
+	connect := func(port net.Port) (*ServerWorker, *pipe.Writer, *pipe.Reader) {
+		ctx := newTestContext(user, net.TCPDestination(net.ParseAddress("10.0.0.3"), port), target)
+		uplinkReader, uplinkWriter := pipe.New()
+		downlinkReader, downlinkWriter := pipe.New()
+		worker, err := NewServerWorker(ctx, dispatcher, &transport.Link{
+			Reader: uplinkReader,
+			Writer: downlinkWriter,
+		})
+		common.Must(err)
+		return worker, uplinkWriter, downlinkReader
+	}
+	send := func(output buf.Writer, payload string) {
+		writer := NewWriter(1, target, output, protocol.TransferTypePacket)
+		writer.globalID = globalID
+		b := buf.New()
+		common.Must2(b.WriteString(payload))
+		common.Must(writer.WriteMultiBuffer(buf.MultiBuffer{b}))
+	}
+	readResponse := func(downlink *pipe.Reader) (FrameMetadata, string) {
+		reader := &buf.BufferedReader{Reader: downlink}
+		var meta FrameMetadata
+		if err := meta.Unmarshal(reader); err != nil {
+			t.Fatal(err)
+		}
+		if !meta.Option.Has(OptionData) {
+			return meta, ""
+		}
+		mb, err := NewPacketReader(reader, &meta.Target).ReadMultiBuffer()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer buf.ReleaseMulti(mb)
+		return meta, mb.String()
+	}
+
+	worker1, uplink1, downlink1 := connect(40000)
+	send(uplink1, "first")
+	remote := dispatcher.remote(t)
+	if payload, dest := readPacket(t, remote.Reader); payload != "first" || dest != target {
+		t.Fatalf("unexpected packet %q to %v", payload, dest)
+	}
+
+	_, uplink2, downlink2 := connect(40001)
+	send(uplink2, "second")
+	if payload, _ := readPacket(t, remote.Reader); payload != "second" {
+		t.Fatalf("unexpected packet %q after reconnect", payload)
+	}
+	if atomic.LoadInt32(&dispatcher.dispatched) != 1 {
+		t.Fatal("reconnect dispatched a new session: ", dispatcher.dispatched)
+	}
+	if meta, _ := readResponse(downlink1); meta.SessionStatus != SessionStatusEnd {
+		t.Fatalf("replaced session was not ended: %+v", meta)
+	}
+	if n := worker1.ActiveConnections(); n != 0 {
+		t.Fatal("replaced session is still registered: ", n)
+	}
+
+	response := buf.New()
+	common.Must2(response.WriteString("response"))
+	from := net.UDPDestination(net.ParseAddress("9.9.9.9"), 5353)
+	net.SetPacketDestination(response, from)
+	common.Must(remote.Writer.WriteMultiBuffer(buf.MultiBuffer{response}))
+	meta, payload := readResponse(downlink2)
+	if meta.SessionStatus != SessionStatusKeep || meta.Target != from {
+		t.Fatalf("unexpected response frame %+v", meta)
+	}
+	if payload != "response" {
+		t.Fatalf("unexpected response %q", payload)
+	}
+	common.Must(uplink1.Close())
+	common.Must(uplink2.Close())
+}
+
+func TestGlobalIDWithoutUserFallsBackToSession(t *testing.T) {
+	target := net.UDPDestination(net.ParseAddress("8.8.8.8"), 53)
+	globalID := GlobalIDFromContext(newTestContext(nil, net.UDPDestination(net.ParseAddress("192.168.1.3"), 5000), target))
+	dispatcher := newTestDispatcher()
+	for port := net.Port(40000); port < 40002; port++ {
+		uplinkReader, uplinkWriter := pipe.New()
+		_, downlinkWriter := pipe.New(pipe.DiscardOverflow())
+		common.Must2(NewServerWorker(newTestContext(nil, net.TCPDestination(net.ParseAddress("10.0.0.5"), port), target), dispatcher, &transport.Link{
+			Reader: uplinkReader,
+			Writer: downlinkWriter,
+		}))
+		writer := NewWriter(1, target, uplinkWriter, protocol.TransferTypePacket)
+		writer.globalID = globalID
+		b := buf.New()
+		common.Must2(b.WriteString("anonymous"))
+		common.Must(writer.WriteMultiBuffer(buf.MultiBuffer{b}))
+		if payload, _ := readPacket(t, dispatcher.remote(t).Reader); payload != "anonymous" {
+			t.Fatalf("unexpected packet %q", payload)
+		}
+		common.Must(uplinkWriter.Close())
+	}
+	if n := atomic.LoadInt32(&dispatcher.dispatched); n != 2 {
+		t.Fatal("anonymous flows shared a global session: ", n)
+	}
+}
+
+func TestGlobalSessionOwner(t *testing.T) {
+	target := net.UDPDestination(net.ParseAddress("8.8.8.8"), 53)
+	source := net.TCPDestination(net.ParseAddress("10.0.0.4"), 40000)
+	owner := &protocol.MemoryUser{Email: "owner@example.com", Account: testAccount("owner")}
+	impostor := &protocol.MemoryUser{Email: "owner@example.com", Account: testAccount("impostor")}
+	other := &protocol.MemoryUser{Email: "other@example.com", Account: testAccount("other")}
+	ctx := newTestContext(owner, source, target)
+	id := GlobalIDFromContext(ctx)
+	dispatcher := newTestDispatcher()
+	create := func() (*transport.Link, error) {
+		return dispatcher.Dispatch(ctx, target)
+	}
+	gs, created, err := globalSessions.GetOrCreate(ctx, id, create)
+	if err != nil || !created {
+		t.Fatal("failed to create global session: ", err)
+	}
+	defer globalSessions.Remove(gs)
+	testCases := []struct {
+		name    string
+		ctx     context.Context
+		created bool
+		err     bool
+	}{
+		{
+			name: "owner",
+			ctx:  newTestContext(owner, source, target),
+		},
+		{
+			name: "same email, other account",
+			ctx:  newTestContext(impostor, source, target),
+			err:  true,
+		},
+		{
+			name:    "other user",
+			ctx:     newTestContext(other, source, target),
+			created: true,
+		},
+		{
+			name: "anonymous",
+			ctx:  newTestContext(nil, source, target),
+			err:  true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s, created, err := globalSessions.GetOrCreate(tc.ctx, id, create)
+			if (err != nil) != tc.err {
+				t.Fatal("unexpected error: ", err)
+			}
+			if created != tc.created {
+				t.Fatal("unexpected created: ", created)
+			}
+			if err == nil && !created && s != gs {
+				t.Fatal("attached to a different session")
+			}
+			if created {
+				globalSessions.Remove(s)
+			}
+		})
+	}
+}
@@ -0,0 +1,14 @@
+package net
+
+import (
+	"github.com/vmessocket/vmessocket/common/buf"
+)
+
+func PacketDestination(b *buf.Buffer) (Destination, bool) {
+	dest, ok := b.UDP.(Destination)
+	return dest, ok
+}
+
+func SetPacketDestination(b *buf.Buffer, dest Destination) {
+	b.UDP = dest
+}
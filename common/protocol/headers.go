@@ -11,6 +11,7 @@ import (
 const (
 	RequestCommandTCP = RequestCommand(0x01)
 	RequestCommandUDP = RequestCommand(0x02)
+	RequestCommandMux = RequestCommand(0x03)
 	RequestOptionChunkStream         bitmask.Byte = 0x01
 	RequestOptionConnectionReuse     bitmask.Byte = 0x02
 	RequestOptionChunkMasking        bitmask.Byte = 0x04
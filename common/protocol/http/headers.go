@@ -1,5 +1,7 @@
 package http
 
+//go:generate go run github.com/vmessocket/vmessocket/common/errors/errorgen
+
 import (
 	"net/http"
 	"strconv"
@@ -8,6 +10,15 @@ import (
 	"github.com/vmessocket/vmessocket/common/net"
 )
 
+func isTrustedProxy(ip net.IP, trustedProxies []*net.IPNet) bool {
+	for _, trusted := range trustedProxies {
+		if trusted.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
 func ParseHost(rawHost string, defaultPort net.Port) (net.Destination, error) {
 	port := defaultPort
 	host, rawPort, err := net.SplitHostPort(rawHost)
@@ -27,6 +38,35 @@ func ParseHost(rawHost string, defaultPort net.Port) (net.Destination, error) {
 	return net.TCPDestination(net.ParseAddress(host), port), nil
 }
 
+func ParseTrustedProxies(proxies []string) ([]*net.IPNet, error) {
+	trusted := make([]*net.IPNet, 0, len(proxies))
+	for _, proxy := range proxies {
+		proxy = strings.TrimSpace(proxy)
+		if !strings.Contains(proxy, "/") {
+			ip := net.ParseIP(proxy)
+			if ip == nil {
+				return nil, newError("invalid trusted proxy: ", proxy)
+			}
+			bits := 8 * net.IPv6len
+			if ip4 := ip.To4(); ip4 != nil {
+				ip = ip4
+				bits = 8 * net.IPv4len
+			}
+			trusted = append(trusted, &net.IPNet{
+				IP:   ip,
+				Mask: net.CIDRMask(bits, bits),
+			})
+			continue
+		}
+		_, ipNet, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return nil, newError("invalid trusted proxy: ", proxy).Base(err)
+		}
+		trusted = append(trusted, ipNet)
+	}
+	return trusted, nil
+}
+
 func ParseXForwardedFor(header http.Header) []net.Address {
 	xff := header.Get("X-Forwarded-For")
 	if xff == "" {
@@ -57,3 +97,31 @@ func RemoveHopByHopHeaders(header http.Header) {
 		header.Del(strings.TrimSpace(h))
 	}
 }
+
+func ResolveRemoteAddr(header http.Header, remoteAddr net.Addr, trustedProxies []*net.IPNet) net.Addr {
+	if tcpAddr, ok := remoteAddr.(*net.TCPAddr); !ok || !isTrustedProxy(tcpAddr.IP, trustedProxies) {
+		return remoteAddr
+	}
+	forwardedAddrs := ParseXForwardedFor(header)
+	for idx := len(forwardedAddrs) - 1; idx >= 0; idx-- {
+		addr := forwardedAddrs[idx]
+		if !addr.Family().IsIP() {
+			break
+		}
+		if idx == 0 || !isTrustedProxy(addr.IP(), trustedProxies) {
+			return &net.TCPAddr{
+				IP:   addr.IP(),
+				Port: 0,
+			}
+		}
+	}
+	for _, name := range []string{"X-Real-IP", "CF-Connecting-IP"} {
+		if addr := net.ParseAddress(header.Get(name)); addr.Family().IsIP() {
+			return &net.TCPAddr{
+				IP:   addr.IP(),
+				Port: 0,
+			}
+		}
+	}
+	return remoteAddr
+}
@@ -0,0 +1,108 @@
+package http_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/vmessocket/vmessocket/common"
+	"github.com/vmessocket/vmessocket/common/net"
+	. "github.com/vmessocket/vmessocket/common/protocol/http"
+)
+
+func TestParseTrustedProxies(t *testing.T) {
+	testCases := []struct {
+		name    string
+		proxies []string
+		err     bool
+	}{
+		{
+			name:    "ipv4",
+			proxies: []string{"127.0.0.1"},
+		},
+		{
+			name:    "ipv6 and cidr",
+			proxies: []string{"::1", " 10.0.0.0/8 "},
+		},
+		{
+			name:    "invalid ip",
+			proxies: []string{"localhost"},
+			err:     true,
+		},
+		{
+			name:    "invalid cidr",
+			proxies: []string{"10.0.0.0/33"},
+			err:     true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			trusted, err := ParseTrustedProxies(tc.proxies)
+			if (err != nil) != tc.err {
+				t.Fatal("unexpected error: ", err)
+			}
+			if err == nil && len(trusted) != len(tc.proxies) {
+				t.Fatal("unexpected trusted proxies: ", trusted)
+			}
+		})
+	}
+}
+
+func TestResolveRemoteAddr(t *testing.T) {
+	trusted, err := ParseTrustedProxies([]string{"127.0.0.1", "10.0.0.0/8"})
+	common.Must(err)
+	peer := &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: 40000}
+	untrusted := &net.TCPAddr{IP: net.ParseIP("192.0.2.1"), Port: 40000}
+	testCases := []struct {
+		name     string
+		header   map[string]string
+		remote   net.Addr
+		expected string
+	}{
+		{
+			name:     "untrusted peer",
+			header:   map[string]string{"X-Forwarded-For": "198.51.100.1"},
+			remote:   untrusted,
+			expected: "192.0.2.1:40000",
+		},
+		{
+			name:     "trusted peer",
+			header:   map[string]string{"X-Forwarded-For": "198.51.100.1"},
+			remote:   peer,
+			expected: "198.51.100.1:0",
+		},
+		{
+			name:     "skip trusted hops",
+			header:   map[string]string{"X-Forwarded-For": "203.0.113.9, 198.51.100.1, 10.1.2.3"},
+			remote:   peer,
+			expected: "198.51.100.1:0",
+		},
+		{
+			name:     "spoofed leading entry",
+			header:   map[string]string{"X-Forwarded-For": "203.0.113.9, 198.51.100.1"},
+			remote:   peer,
+			expected: "198.51.100.1:0",
+		},
+		{
+			name:     "malformed forwarded for",
+			header:   map[string]string{"X-Forwarded-For": "example.com", "X-Real-IP": "198.51.100.2"},
+			remote:   peer,
+			expected: "198.51.100.2:0",
+		},
+		{
+			name:     "no headers",
+			remote:   peer,
+			expected: "127.0.0.1:40000",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			header := http.Header{}
+			for key, value := range tc.header {
+				header.Set(key, value)
+			}
+			if addr := ResolveRemoteAddr(header, tc.remote, trusted); addr.String() != tc.expected {
+				t.Fatalf("expected %s, got %s", tc.expected, addr)
+			}
+		})
+	}
+}
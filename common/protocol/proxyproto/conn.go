@@ -0,0 +1,134 @@
+package proxyproto
+
+import (
+	"bufio"
+	"errors"
+	gonet "net"
+	"time"
+
+	"github.com/vmessocket/vmessocket/common/net"
+	"github.com/vmessocket/vmessocket/common/signal/done"
+)
+
+const headerTimeout = 5 * time.Second
+
+type acceptResult struct {
+	conn net.Conn
+	err  error
+}
+
+type conn struct {
+	net.Conn
+	reader      *bufio.Reader
+	source      net.Addr
+	destination net.Addr
+}
+
+type listener struct {
+	net.Listener
+	accepted chan acceptResult
+	done     *done.Instance
+}
+
+func newConn(c net.Conn) (*conn, error) {
+	pc := &conn{
+		Conn:   c,
+		reader: bufio.NewReaderSize(c, 256),
+	}
+	if err := c.SetReadDeadline(time.Now().Add(headerTimeout)); err != nil {
+		return nil, err
+	}
+	source, destination, err := readHeader(pc.reader)
+	if err != nil {
+		return nil, newError("failed to read PROXY protocol header from ", c.RemoteAddr()).Base(err)
+	}
+	pc.source = source
+	pc.destination = destination
+	if err := c.SetReadDeadline(time.Time{}); err != nil {
+		return nil, err
+	}
+	return pc, nil
+}
+
+func NewListener(l net.Listener) net.Listener {
+	pl := &listener{
+		Listener: l,
+		accepted: make(chan acceptResult),
+		done:     done.New(),
+	}
+	go pl.keepAccepting()
+	return pl
+}
+
+func (l *listener) Accept() (net.Conn, error) {
+	select {
+	case r := <-l.accepted:
+		return r.conn, r.err
+	case <-l.done.Wait():
+		return nil, gonet.ErrClosed
+	}
+}
+
+func (l *listener) Close() error {
+	l.done.Close()
+	return l.Listener.Close()
+}
+
+func (l *listener) deliver(r acceptResult) bool {
+	select {
+	case l.accepted <- r:
+		return true
+	case <-l.done.Wait():
+		return false
+	}
+}
+
+func (l *listener) handshake(c net.Conn) {
+	pc, err := newConn(c)
+	if err != nil {
+		newError("dropping connection").Base(err).AtInfo().WriteToLog()
+		c.Close()
+		return
+	}
+	if !l.deliver(acceptResult{conn: pc}) {
+		c.Close()
+	}
+}
+
+func (l *listener) keepAccepting() {
+	for {
+		c, err := l.Listener.Accept()
+		if err != nil {
+			if !l.deliver(acceptResult{err: err}) {
+				return
+			}
+			if errors.Is(err, gonet.ErrClosed) {
+				l.done.Close()
+				return
+			}
+			continue
+		}
+		go l.handshake(c)
+	}
+}
+
+func (c *conn) LocalAddr() net.Addr {
+	if c.destination != nil {
+		return c.destination
+	}
+	return c.Conn.LocalAddr()
+}
+
+func (c *conn) Read(b []byte) (int, error) {
+	if c.reader.Buffered() > 0 {
+		return c.reader.Read(b)
+	}
+	return c.Conn.Read(b)
+}
+
+func (c *conn) RemoteAddr() net.Addr {
+	if c.source != nil {
+		return c.source
+	}
+	return c.Conn.RemoteAddr()
+}
@@ -0,0 +1,77 @@
+package proxyproto
+
+import (
+	"io"
+	gonet "net"
+	"testing"
+	"time"
+
+	"github.com/vmessocket/vmessocket/common"
+	"github.com/vmessocket/vmessocket/common/net"
+)
+
+func dialListener(t *testing.T, l net.Listener, data string) gonet.Conn {
+	t.Helper()
+	c, err := gonet.Dial("tcp", l.Addr().String())
+	common.Must(err)
+	t.Cleanup(func() {
+		c.Close()
+	})
+	if len(data) > 0 {
+		common.Must2(io.WriteString(c, data))
+	}
+	return c
+}
+
+func TestListenerAcceptsWithoutWaitingForSlowHeaders(t *testing.T) {
+	inner, err := gonet.Listen("tcp", "127.0.0.1:0")
+	common.Must(err)
+	l := NewListener(inner)
+	defer l.Close()
+	silent := dialListener(t, l, "")
+	broken := dialListener(t, l, "GET / HTTP/1.1\r\n\r\n")
+	dialListener(t, l, "PROXY TCP4 192.0.2.1 198.51.100.1 1234 443\r\npayload")
+	start := time.Now()
+	c, err := l.Accept()
+	common.Must(err)
+	defer c.Close()
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatal("accept waited ", elapsed, " behind a client that sent no header")
+	}
+	if c.RemoteAddr().String() != "192.0.2.1:1234" || c.LocalAddr().String() != "198.51.100.1:443" {
+		t.Error("unexpected addresses ", c.RemoteAddr(), " -> ", c.LocalAddr())
+	}
+	payload := make([]byte, len("payload"))
+	common.Must2(io.ReadFull(c, payload))
+	if string(payload) != "payload" {
+		t.Errorf("read %q after the header", payload)
+	}
+	broken.SetReadDeadline(time.Now().Add(time.Second))
+	if _, err := broken.Read(make([]byte, 1)); err != io.EOF {
+		t.Error("connection without a header was not dropped: ", err)
+	}
+	silent.SetReadDeadline(time.Now().Add(10 * time.Millisecond))
+	if _, err := silent.Read(make([]byte, 1)); err == io.EOF {
+		t.Error("silent connection was dropped before the header timeout")
+	}
+}
+
+func TestListenerCloseUnblocksAccept(t *testing.T) {
+	inner, err := gonet.Listen("tcp", "127.0.0.1:0")
+	common.Must(err)
+	l := NewListener(inner)
+	accepted := make(chan error, 1)
+	go func() {
+		_, err := l.Accept()
+		accepted <- err
+	}()
+	common.Must(l.Close())
+	select {
+	case err := <-accepted:
+		if err == nil {
+			t.Error("accept returned a connection after close")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("accept still blocked after close")
+	}
+}
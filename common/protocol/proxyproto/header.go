@@ -0,0 +1,209 @@
+package proxyproto
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/vmessocket/vmessocket/common/net"
+	"github.com/vmessocket/vmessocket/common/session"
+)
+
+const (
+	maxV1HeaderLength = 107
+	v2CommandLocal    = 0x20
+	v2CommandProxy    = 0x21
+	v2FamilyTCP4      = 0x11
+	v2FamilyTCP6      = 0x21
+	v2FamilyUnspec    = 0x00
+)
+
+var (
+	v1Signature = []byte("PROXY ")
+	v2Signature = []byte("\r\n\r\n\x00\r\nQUIT\n")
+)
+
+func EncodeHeader(version uint32, source net.Addr, destination net.Addr) ([]byte, error) {
+	src, srcOk := source.(*net.TCPAddr)
+	dst, dstOk := destination.(*net.TCPAddr)
+	ipv4 := srcOk && dstOk && src.IP.To4() != nil && dst.IP.To4() != nil
+	switch version {
+	case 1:
+		if !srcOk || !dstOk {
+			return []byte("PROXY UNKNOWN\r\n"), nil
+		}
+		family := "TCP6"
+		if ipv4 {
+			family = "TCP4"
+		}
+		return []byte("PROXY " + family + " " + formatV1IP(src.IP, ipv4) + " " + formatV1IP(dst.IP, ipv4) + " " + strconv.Itoa(src.Port) + " " + strconv.Itoa(dst.Port) + "\r\n"), nil
+	case 2:
+		header := bytes.NewBuffer(make([]byte, 0, 16+36))
+		header.Write(v2Signature)
+		if !srcOk || !dstOk {
+			header.Write([]byte{v2CommandLocal, v2FamilyUnspec, 0, 0})
+			return header.Bytes(), nil
+		}
+		if ipv4 {
+			header.Write([]byte{v2CommandProxy, v2FamilyTCP4, 0, 12})
+			header.Write(src.IP.To4())
+			header.Write(dst.IP.To4())
+		} else {
+			header.Write([]byte{v2CommandProxy, v2FamilyTCP6, 0, 36})
+			header.Write(src.IP.To16())
+			header.Write(dst.IP.To16())
+		}
+		binary.Write(header, binary.BigEndian, uint16(src.Port))
+		binary.Write(header, binary.BigEndian, uint16(dst.Port))
+		return header.Bytes(), nil
+	default:
+		return nil, newError("unsupported PROXY protocol version: ", version)
+	}
+}
+
+func formatV1IP(ip net.IP, ipv4 bool) string {
+	if ip4 := ip.To4(); ip4 != nil {
+		if ipv4 {
+			return ip4.String()
+		}
+		return "::ffff:" + ip4.String()
+	}
+	return ip.String()
+}
+
+func readHeader(reader *bufio.Reader) (net.Addr, net.Addr, error) {
+	first, err := reader.Peek(1)
+	if err != nil {
+		return nil, nil, err
+	}
+	switch first[0] {
+	case v1Signature[0]:
+		return readV1Header(reader)
+	case v2Signature[0]:
+		return readV2Header(reader)
+	default:
+		return nil, nil, newError("PROXY protocol header is missing")
+	}
+}
+
+func readV1Header(reader *bufio.Reader) (net.Addr, net.Addr, error) {
+	line := make([]byte, 0, maxV1HeaderLength)
+	for {
+		b, err := reader.ReadByte()
+		if err != nil {
+			return nil, nil, err
+		}
+		line = append(line, b)
+		if b == '\n' {
+			break
+		}
+		if len(line) >= maxV1HeaderLength {
+			return nil, nil, newError("PROXY protocol v1 header is too long")
+		}
+	}
+	if !bytes.HasPrefix(line, v1Signature) || !bytes.HasSuffix(line, []byte("\r\n")) {
+		return nil, nil, newError("invalid PROXY protocol v1 header")
+	}
+	fields := strings.Split(string(line[len(v1Signature):len(line)-2]), " ")
+	switch fields[0] {
+	case "UNKNOWN":
+		return nil, nil, nil
+	case "TCP4", "TCP6":
+	default:
+		return nil, nil, newError("unsupported PROXY protocol v1 family: ", fields[0])
+	}
+	if len(fields) != 5 {
+		return nil, nil, newError("invalid PROXY protocol v1 header")
+	}
+	source, err := parseV1Address(fields[0], fields[1], fields[3])
+	if err != nil {
+		return nil, nil, err
+	}
+	destination, err := parseV1Address(fields[0], fields[2], fields[4])
+	if err != nil {
+		return nil, nil, err
+	}
+	return source, destination, nil
+}
+
+func parseV1Address(family string, host string, port string) (*net.TCPAddr, error) {
+	ip := net.ParseIP(host)
+	if ip == nil || (family == "TCP4") != (ip.To4() != nil && !strings.Contains(host, ":")) {
+		return nil, newError("invalid address in PROXY protocol v1 header: ", host)
+	}
+	p, err := strconv.ParseUint(port, 10, 16)
+	if err != nil {
+		return nil, newError("invalid port in PROXY protocol v1 header: ", port).Base(err)
+	}
+	return &net.TCPAddr{
+		IP:   ip,
+		Port: int(p),
+	}, nil
+}
+
+func readV2Header(reader *bufio.Reader) (net.Addr, net.Addr, error) {
+	header := make([]byte, 16)
+	if _, err := io.ReadFull(reader, header); err != nil {
+		return nil, nil, err
+	}
+	if !bytes.Equal(header[:12], v2Signature) {
+		return nil, nil, newError("invalid PROXY protocol v2 signature")
+	}
+	if header[12]>>4 != 2 {
+		return nil, nil, newError("unsupported PROXY protocol v2 version: ", header[12]>>4)
+	}
+	payload := make([]byte, binary.BigEndian.Uint16(header[14:16]))
+	if _, err := io.ReadFull(reader, payload); err != nil {
+		return nil, nil, err
+	}
+	switch header[12] {
+	case v2CommandLocal:
+		return nil, nil, nil
+	case v2CommandProxy:
+	default:
+		return nil, nil, newError("unsupported PROXY protocol v2 command: ", header[12]&0x0F)
+	}
+	var ipLength int
+	switch header[13] >> 4 {
+	case 1:
+		ipLength = net.IPv4len
+	case 2:
+		ipLength = net.IPv6len
+	default:
+		return nil, nil, nil
+	}
+	if len(payload) < 2*ipLength+4 {
+		return nil, nil, newError("PROXY protocol v2 address block is too short")
+	}
+	source := &net.TCPAddr{
+		IP:   net.IP(payload[:ipLength]),
+		Port: int(binary.BigEndian.Uint16(payload[2*ipLength:])),
+	}
+	destination := &net.TCPAddr{
+		IP:   net.IP(payload[ipLength : 2*ipLength]),
+		Port: int(binary.BigEndian.Uint16(payload[2*ipLength+2:])),
+	}
+	return source, destination, nil
+}
+
+func WriteHeader(ctx context.Context, writer io.Writer, version uint32, destination net.Addr) error {
+	var source net.Addr
+	if inbound := session.InboundFromContext(ctx); inbound != nil && inbound.Source.IsValid() && inbound.Source.Address.Family().IsIP() {
+		source = &net.TCPAddr{
+			IP:   inbound.Source.Address.IP(),
+			Port: int(inbound.Source.Port),
+		}
+	}
+	header, err := EncodeHeader(version, source, destination)
+	if err != nil {
+		return err
+	}
+	if _, err := writer.Write(header); err != nil {
+		return newError("failed to write PROXY protocol header").Base(err)
+	}
+	return nil
+}
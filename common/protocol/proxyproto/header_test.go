@@ -0,0 +1,139 @@
+package proxyproto
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/vmessocket/vmessocket/common/net"
+)
+
+func addrString(addr net.Addr) string {
+	if addr == nil {
+		return ""
+	}
+	return addr.String()
+}
+
+func tcpAddr(host string, port int) *net.TCPAddr {
+	return &net.TCPAddr{IP: net.ParseIP(host), Port: port}
+}
+
+func v2Header(command byte, family byte, payload []byte) []byte {
+	header := append([]byte{}, v2Signature...)
+	header = append(header, command, family, byte(len(payload)>>8), byte(len(payload)))
+	return append(header, payload...)
+}
+
+func TestReadHeader(t *testing.T) {
+	v4Block := []byte{192, 0, 2, 1, 198, 51, 100, 1, 0x04, 0xd2, 0x01, 0xbb}
+	testCases := []struct {
+		input       []byte
+		source      string
+		destination string
+	}{
+		{[]byte("PROXY TCP4 192.0.2.1 198.51.100.1 1234 443\r\n"), "192.0.2.1:1234", "198.51.100.1:443"},
+		{[]byte("PROXY TCP6 2001:db8::1 2001:db8::2 1234 443\r\n"), "[2001:db8::1]:1234", "[2001:db8::2]:443"},
+		{[]byte("PROXY TCP6 ::ffff:192.0.2.1 2001:db8::2 1234 443\r\n"), "192.0.2.1:1234", "[2001:db8::2]:443"},
+		{[]byte("PROXY UNKNOWN ignored fields\r\n"), "", ""},
+		{v2Header(v2CommandProxy, v2FamilyTCP4, v4Block), "192.0.2.1:1234", "198.51.100.1:443"},
+		{v2Header(v2CommandProxy, v2FamilyTCP4, append(v4Block, 0x04, 0x00, 0x01, 0x00)), "192.0.2.1:1234", "198.51.100.1:443"},
+		{v2Header(v2CommandLocal, v2FamilyUnspec, nil), "", ""},
+		{v2Header(v2CommandProxy, 0x31, make([]byte, 216)), "", ""},
+	}
+	for _, tc := range testCases {
+		reader := bufio.NewReader(io.MultiReader(bytes.NewReader(tc.input), strings.NewReader("payload")))
+		source, destination, err := readHeader(reader)
+		if err != nil {
+			t.Errorf("%q: %v", tc.input, err)
+			continue
+		}
+		if got := addrString(source); got != tc.source {
+			t.Errorf("%q: source %q, want %q", tc.input, got, tc.source)
+		}
+		if got := addrString(destination); got != tc.destination {
+			t.Errorf("%q: destination %q, want %q", tc.input, got, tc.destination)
+		}
+		if rest, _ := io.ReadAll(reader); string(rest) != "payload" {
+			t.Errorf("%q: header consumed payload, left %q", tc.input, rest)
+		}
+	}
+}
+
+func TestReadHeaderRejectsMalformed(t *testing.T) {
+	for _, input := range [][]byte{
+		nil,
+		[]byte("GET / HTTP/1.1\r\n\r\n"),
+		[]byte("PROXZ TCP4 192.0.2.1 198.51.100.1 1234 443\r\n"),
+		[]byte("PROXY TCP4 192.0.2.1 198.51.100.1 1234"),
+		append([]byte("PROXY TCP4 "), bytes.Repeat([]byte("1"), maxV1HeaderLength)...),
+		[]byte("PROXY TCP4 192.0.2.1 198.51.100.1 1234 443\n"),
+		[]byte("PROXY UDP4 192.0.2.1 198.51.100.1 1234 443\r\n"),
+		[]byte("PROXY TCP4 192.0.2.1 198.51.100.1 1234\r\n"),
+		[]byte("PROXY TCP4 192.0.2.1 198.51.100.1 1234 443 80\r\n"),
+		[]byte("PROXY TCP4  192.0.2.1 198.51.100.1 1234 443\r\n"),
+		[]byte("PROXY TCP4 192.0.2 198.51.100.1 1234 443\r\n"),
+		[]byte("PROXY TCP4 192.0.2.1 example.com 1234 443\r\n"),
+		[]byte("PROXY TCP4 2001:db8::1 198.51.100.1 1234 443\r\n"),
+		[]byte("PROXY TCP6 192.0.2.1 2001:db8::2 1234 443\r\n"),
+		[]byte("PROXY TCP4 192.0.2.1 198.51.100.1 65536 443\r\n"),
+		[]byte("PROXY TCP4 192.0.2.1 198.51.100.1 1234 -1\r\n"),
+		v2Signature[:8],
+		append([]byte("\r\n\r\n\x00\r\nQUIZ\n"), v2CommandLocal, v2FamilyUnspec, 0, 0),
+		v2Header(0x10, v2FamilyUnspec, nil),
+		v2Header(0x22, v2FamilyUnspec, nil),
+		v2Header(v2CommandProxy, v2FamilyTCP4, make([]byte, 12))[:20],
+		v2Header(v2CommandProxy, v2FamilyTCP4, make([]byte, 11)),
+		v2Header(v2CommandProxy, v2FamilyTCP6, make([]byte, 12)),
+	} {
+		if source, destination, err := readHeader(bufio.NewReader(bytes.NewReader(input))); err == nil {
+			t.Errorf("%q: accepted as %v -> %v", input, source, destination)
+		}
+	}
+}
+
+func TestEncodeHeaderRoundTrip(t *testing.T) {
+	v4, otherV4 := tcpAddr("192.0.2.1", 1234), tcpAddr("198.51.100.1", 443)
+	v6, otherV6 := tcpAddr("2001:db8::1", 1234), tcpAddr("2001:db8::2", 443)
+	testCases := []struct {
+		source      net.Addr
+		destination net.Addr
+		v1          string
+	}{
+		{v4, otherV4, "PROXY TCP4 192.0.2.1 198.51.100.1 1234 443\r\n"},
+		{v6, otherV6, "PROXY TCP6 2001:db8::1 2001:db8::2 1234 443\r\n"},
+		{v4, otherV6, "PROXY TCP6 ::ffff:192.0.2.1 2001:db8::2 1234 443\r\n"},
+		{v6, otherV4, "PROXY TCP6 2001:db8::1 ::ffff:198.51.100.1 1234 443\r\n"},
+		{nil, otherV4, "PROXY UNKNOWN\r\n"},
+	}
+	for _, tc := range testCases {
+		for _, version := range []uint32{1, 2} {
+			header, err := EncodeHeader(version, tc.source, tc.destination)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if version == 1 && string(header) != tc.v1 {
+				t.Errorf("v1 header %q, want %q", header, tc.v1)
+			}
+			source, destination, err := readHeader(bufio.NewReader(bytes.NewReader(header)))
+			if err != nil {
+				t.Errorf("v%d %v -> %v: %v", version, tc.source, tc.destination, err)
+				continue
+			}
+			if tc.source == nil {
+				if source != nil || destination != nil {
+					t.Errorf("v%d: expected no addresses, got %v -> %v", version, source, destination)
+				}
+				continue
+			}
+			if addrString(source) != addrString(tc.source) || addrString(destination) != addrString(tc.destination) {
+				t.Errorf("v%d: %v -> %v came back as %v -> %v", version, tc.source, tc.destination, source, destination)
+			}
+		}
+	}
+	if _, err := EncodeHeader(3, nil, nil); err == nil {
+		t.Error("encoded unsupported version 3")
+	}
+}
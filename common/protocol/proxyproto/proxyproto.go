@@ -0,0 +1,3 @@
+package proxyproto
+
+//go:generate go run github.com/vmessocket/vmessocket/common/errors/errorgen
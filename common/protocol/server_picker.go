@@ -1,6 +1,28 @@
 package protocol
 
-import "sync"
+import (
+	"sync"
+	"time"
+
+	"github.com/vmessocket/vmessocket/common/dice"
+)
+
+const (
+	defaultEjectDuration = 30 * time.Second
+	defaultMaxFailures   = 3
+)
+
+type LeastFailuresServerPicker struct {
+	tracker *serverHealthTracker
+}
+
+type LowestRTTServerPicker struct {
+	tracker *serverHealthTracker
+}
+
+type RandomServerPicker struct {
+	serverlist *ServerList
+}
 
 type RoundRobinServerPicker struct {
 	sync.Mutex
@@ -8,6 +30,20 @@ type RoundRobinServerPicker struct {
 	nextIndex  uint32
 }
 
+type serverHealth struct {
+	failures     uint32
+	ejectedUntil time.Time
+	rtt          time.Duration
+}
+
+type serverHealthTracker struct {
+	sync.Mutex
+	serverlist    *ServerList
+	stats         map[*ServerSpec]*serverHealth
+	maxFailures   uint32
+	ejectDuration time.Duration
+}
+
 type ServerList struct {
 	sync.RWMutex
 	servers []*ServerSpec
@@ -17,6 +53,28 @@ type ServerPicker interface {
 	PickServer() *ServerSpec
 }
 
+type ServerReporter interface {
+	ReportServer(server *ServerSpec, rtt time.Duration, err error)
+}
+
+func NewLeastFailuresServerPicker(serverlist *ServerList, maxFailures uint32, ejectDuration time.Duration) *LeastFailuresServerPicker {
+	return &LeastFailuresServerPicker{
+		tracker: newServerHealthTracker(serverlist, maxFailures, ejectDuration),
+	}
+}
+
+func NewLowestRTTServerPicker(serverlist *ServerList, maxFailures uint32, ejectDuration time.Duration) *LowestRTTServerPicker {
+	return &LowestRTTServerPicker{
+		tracker: newServerHealthTracker(serverlist, maxFailures, ejectDuration),
+	}
+}
+
+func NewRandomServerPicker(serverlist *ServerList) *RandomServerPicker {
+	return &RandomServerPicker{
+		serverlist: serverlist,
+	}
+}
+
 func NewRoundRobinServerPicker(serverlist *ServerList) *RoundRobinServerPicker {
 	return &RoundRobinServerPicker{
 		serverlist: serverlist,
@@ -24,6 +82,21 @@ func NewRoundRobinServerPicker(serverlist *ServerList) *RoundRobinServerPicker {
 	}
 }
 
+func newServerHealthTracker(serverlist *ServerList, maxFailures uint32, ejectDuration time.Duration) *serverHealthTracker {
+	if maxFailures == 0 {
+		maxFailures = defaultMaxFailures
+	}
+	if ejectDuration <= 0 {
+		ejectDuration = defaultEjectDuration
+	}
+	return &serverHealthTracker{
+		serverlist:    serverlist,
+		stats:         make(map[*ServerSpec]*serverHealth),
+		maxFailures:   maxFailures,
+		ejectDuration: ejectDuration,
+	}
+}
+
 func NewServerList() *ServerList {
 	return &ServerList{}
 }
@@ -34,6 +107,38 @@ func (sl *ServerList) AddServer(server *ServerSpec) {
 	sl.servers = append(sl.servers, server)
 }
 
+func (t *serverHealthTracker) candidates() ([]*ServerSpec, []*serverHealth) {
+	servers := t.serverlist.ValidServers()
+	if len(servers) == 0 {
+		return nil, nil
+	}
+	now := time.Now()
+	live := make(map[*ServerSpec]*serverHealth, len(servers))
+	var healthy []*ServerSpec
+	var healthyStats []*serverHealth
+	var soonest *ServerSpec
+	for _, server := range servers {
+		health, found := t.stats[server]
+		if !found {
+			health = new(serverHealth)
+		}
+		live[server] = health
+		if health.ejectedUntil.After(now) {
+			if soonest == nil || health.ejectedUntil.Before(live[soonest].ejectedUntil) {
+				soonest = server
+			}
+			continue
+		}
+		healthy = append(healthy, server)
+		healthyStats = append(healthyStats, health)
+	}
+	t.stats = live
+	if len(healthy) == 0 {
+		return []*ServerSpec{soonest}, []*serverHealth{live[soonest]}
+	}
+	return healthy, healthyStats
+}
+
 func (sl *ServerList) GetServer(idx uint32) *ServerSpec {
 	sl.Lock()
 	defer sl.Unlock()
@@ -50,6 +155,61 @@ func (sl *ServerList) GetServer(idx uint32) *ServerSpec {
 	}
 }
 
+func (p *LeastFailuresServerPicker) PickServer() *ServerSpec {
+	t := p.tracker
+	t.Lock()
+	defer t.Unlock()
+	servers, stats := t.candidates()
+	if len(servers) == 0 {
+		return nil
+	}
+	var best []*ServerSpec
+	var bestFailures uint32
+	for i, server := range servers {
+		switch {
+		case len(best) == 0 || stats[i].failures < bestFailures:
+			best = []*ServerSpec{server}
+			bestFailures = stats[i].failures
+		case stats[i].failures == bestFailures:
+			best = append(best, server)
+		}
+	}
+	return best[dice.Roll(len(best))]
+}
+
+func (p *LowestRTTServerPicker) PickServer() *ServerSpec {
+	t := p.tracker
+	t.Lock()
+	defer t.Unlock()
+	servers, stats := t.candidates()
+	if len(servers) == 0 {
+		return nil
+	}
+	var unmeasured []*ServerSpec
+	best := -1
+	for i := range servers {
+		if stats[i].rtt == 0 && stats[i].failures == 0 {
+			unmeasured = append(unmeasured, servers[i])
+			continue
+		}
+		if best == -1 || stats[i].failures < stats[best].failures || (stats[i].failures == stats[best].failures && stats[i].rtt < stats[best].rtt) {
+			best = i
+		}
+	}
+	if len(unmeasured) > 0 {
+		return unmeasured[dice.Roll(len(unmeasured))]
+	}
+	return servers[best]
+}
+
+func (p *RandomServerPicker) PickServer() *ServerSpec {
+	servers := p.serverlist.ValidServers()
+	if len(servers) == 0 {
+		return nil
+	}
+	return servers[dice.Roll(len(servers))]
+}
+
 func (p *RoundRobinServerPicker) PickServer() *ServerSpec {
 	p.Lock()
 	defer p.Unlock()
@@ -73,8 +233,59 @@ func (sl *ServerList) removeServer(idx uint32) {
 	sl.servers = sl.servers[:n-1]
 }
 
+func (t *serverHealthTracker) report(server *ServerSpec, rtt time.Duration, err error) {
+	t.Lock()
+	defer t.Unlock()
+	health, found := t.stats[server]
+	if !found {
+		health = new(serverHealth)
+		t.stats[server] = health
+	}
+	if err != nil {
+		health.failures++
+		if health.failures >= t.maxFailures {
+			health.ejectedUntil = time.Now().Add(t.ejectDuration)
+			health.failures = 0
+		}
+		return
+	}
+	health.failures = 0
+	health.ejectedUntil = time.Time{}
+	if health.rtt == 0 {
+		health.rtt = rtt
+	} else {
+		health.rtt = (health.rtt*7 + rtt*3) / 10
+	}
+	if health.rtt <= 0 {
+		health.rtt = time.Nanosecond
+	}
+}
+
+func (p *LeastFailuresServerPicker) ReportServer(server *ServerSpec, rtt time.Duration, err error) {
+	p.tracker.report(server, rtt, err)
+}
+
+func (p *LowestRTTServerPicker) ReportServer(server *ServerSpec, rtt time.Duration, err error) {
+	p.tracker.report(server, rtt, err)
+}
+
 func (sl *ServerList) Size() uint32 {
 	sl.RLock()
 	defer sl.RUnlock()
 	return uint32(len(sl.servers))
 }
+
+func (sl *ServerList) ValidServers() []*ServerSpec {
+	sl.Lock()
+	defer sl.Unlock()
+	servers := make([]*ServerSpec, 0, len(sl.servers))
+	for idx := 0; idx < len(sl.servers); {
+		if !sl.servers[idx].IsValid() {
+			sl.removeServer(uint32(idx))
+			continue
+		}
+		servers = append(servers, sl.servers[idx])
+		idx++
+	}
+	return servers
+}
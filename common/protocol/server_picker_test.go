@@ -0,0 +1,117 @@
+package protocol
+
+import (
+	"testing"
+	"time"
+
+	"github.com/vmessocket/vmessocket/common/net"
+)
+
+var errHandshake = newError("handshake failed")
+
+func indexOf(servers []*ServerSpec, server *ServerSpec) int {
+	for i, s := range servers {
+		if s == server {
+			return i
+		}
+	}
+	return -1
+}
+
+func newServerList(count int) (*ServerList, []*ServerSpec) {
+	list := NewServerList()
+	servers := make([]*ServerSpec, count)
+	for i := range servers {
+		servers[i] = NewServerSpec(net.TCPDestination(net.LocalHostIP, net.Port(10000+i)), AlwaysValid())
+		list.AddServer(servers[i])
+	}
+	return list, servers
+}
+
+func TestLowestRTTServerPickerOrdering(t *testing.T) {
+	list, servers := newServerList(3)
+	picker := NewLowestRTTServerPicker(list, 2, time.Minute)
+	measured := make(map[*ServerSpec]bool)
+	for i := 0; i < 3; i++ {
+		server := picker.PickServer()
+		if measured[server] {
+			t.Fatal("picked a measured server while another one was unmeasured")
+		}
+		measured[server] = true
+		picker.ReportServer(server, time.Duration(30-10*indexOf(servers, server))*time.Millisecond, nil)
+	}
+	testCases := []struct {
+		name   string
+		report func()
+		best   *ServerSpec
+	}{
+		{
+			name:   "lowest rtt",
+			report: func() {},
+			best:   servers[2],
+		},
+		{
+			name: "failure outranks rtt",
+			report: func() {
+				picker.ReportServer(servers[2], 0, errHandshake)
+			},
+			best: servers[1],
+		},
+		{
+			name: "success clears failures",
+			report: func() {
+				picker.ReportServer(servers[2], 10*time.Millisecond, nil)
+			},
+			best: servers[2],
+		},
+		{
+			name: "rtt is smoothed",
+			report: func() {
+				picker.ReportServer(servers[2], 50*time.Millisecond, nil)
+			},
+			best: servers[1],
+		},
+	}
+	for _, tc := range testCases {
+		tc.report()
+		for i := 0; i < 5; i++ {
+			if server := picker.PickServer(); server != tc.best {
+				t.Fatal(tc.name, ": picked ", server.Destination(), ", expected ", tc.best.Destination())
+			}
+		}
+	}
+}
+
+func TestServerPickerEjection(t *testing.T) {
+	pickers := map[string]interface {
+		ServerPicker
+		ServerReporter
+	}{}
+	list, servers := newServerList(2)
+	pickers["least failures"] = NewLeastFailuresServerPicker(list, 2, 100*time.Millisecond)
+	pickers["lowest rtt"] = NewLowestRTTServerPicker(list, 2, 100*time.Millisecond)
+	for name, picker := range pickers {
+		picker.ReportServer(servers[0], time.Millisecond, nil)
+		picker.ReportServer(servers[1], 2*time.Millisecond, nil)
+		picker.ReportServer(servers[0], 0, errHandshake)
+		picker.ReportServer(servers[0], 0, errHandshake)
+		for i := 0; i < 10; i++ {
+			if server := picker.PickServer(); server != servers[1] {
+				t.Fatal(name, ": picked an ejected server")
+			}
+		}
+		picker.ReportServer(servers[1], 0, errHandshake)
+		picker.ReportServer(servers[1], 0, errHandshake)
+		if server := picker.PickServer(); server != servers[0] {
+			t.Fatal(name, ": with every server ejected, expected the one that returns first")
+		}
+		time.Sleep(150 * time.Millisecond)
+		picked := make(map[*ServerSpec]bool)
+		for i := 0; i < 50; i++ {
+			picked[picker.PickServer()] = true
+		}
+		if !picked[servers[0]] {
+			t.Fatal(name, ": server was not picked again after its ejection expired")
+		}
+	}
+}
@@ -10,9 +10,10 @@ import (
 )
 
 type Content struct {
-	Protocol        string
-	Attributes      map[string]string
-	SkipDNSResolve  bool
+	Protocol       string
+	Attributes     map[string]string
+	SkipDNSResolve bool
+	Hops           uint32
 }
 
 type ID uint32
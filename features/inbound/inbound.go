@@ -10,6 +10,7 @@ import (
 
 type Handler interface {
 	common.Runnable
+	Tag() string
 	GetRandomInboundProxy() (interface{}, net.Port, int)
 }
 
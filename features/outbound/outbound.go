@@ -10,6 +10,7 @@ import (
 
 type Handler interface {
 	common.Runnable
+	Tag() string
 	Dispatch(ctx context.Context, link *transport.Link)
 }
 
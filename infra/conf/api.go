@@ -0,0 +1,43 @@
+package conf
+
+import (
+	"strings"
+
+	"github.com/vmessocket/vmessocket/app/commander"
+	logservice "github.com/vmessocket/vmessocket/app/log/command"
+	handlerservice "github.com/vmessocket/vmessocket/app/proxyman/command"
+	"github.com/vmessocket/vmessocket/common/serial"
+	certificateservice "github.com/vmessocket/vmessocket/transport/internet/tls/command"
+)
+
+type APIConfig struct {
+	Tag      string   `json:"tag"`
+	Listen   string   `json:"listen"`
+	Services []string `json:"services"`
+}
+
+func (c *APIConfig) Build() (*commander.Config, error) {
+	if len(c.Tag) == 0 && len(c.Listen) == 0 {
+		return nil, newError("API tag or listen address is required")
+	}
+	services := make([]*serial.TypedMessage, 0, 8)
+	for _, s := range c.Services {
+		switch strings.ToLower(s) {
+		case "certificateservice":
+			services = append(services, serial.ToTypedMessage(&certificateservice.Config{}))
+		case "handlerservice":
+			services = append(services, serial.ToTypedMessage(&handlerservice.Config{}))
+		case "loggerservice":
+			services = append(services, serial.ToTypedMessage(&logservice.Config{}))
+		case "reflectionservice":
+			services = append(services, serial.ToTypedMessage(&commander.ReflectionConfig{}))
+		default:
+			return nil, newError("unknown API service: ", s)
+		}
+	}
+	return &commander.Config{
+		Tag:     c.Tag,
+		Listen:  c.Listen,
+		Service: services,
+	}, nil
+}
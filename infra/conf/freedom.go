@@ -2,41 +2,137 @@ package conf
 
 import (
 	"net"
+	"strings"
 
 	"github.com/golang/protobuf/proto"
 
 	v2net "github.com/vmessocket/vmessocket/common/net"
 	"github.com/vmessocket/vmessocket/common/protocol"
+	"github.com/vmessocket/vmessocket/infra/conf/cfgcommon"
 	"github.com/vmessocket/vmessocket/proxy/freedom"
 )
 
 type FreedomConfig struct {
-	Timeout        *uint32 `json:"timeout"`
-	Redirect       string  `json:"redirect"`
+	DomainStrategy string                  `json:"domainStrategy"`
+	Timeout        *uint32                 `json:"timeout"`
+	Redirect       string                  `json:"redirect"`
+	FallbackDelay  uint32                  `json:"fallbackDelay"`
+	Rewrite        []*FreedomRewriteConfig `json:"rewrite"`
+	Fragment       *FreedomFragmentConfig  `json:"fragment"`
+	ProxyProtocol  uint32                  `json:"sendProxyProtocol"`
+}
+
+type FreedomFragmentConfig struct {
+	Lengths []uint32 `json:"lengths"`
+	Delay   uint32   `json:"delay"`
+}
+
+type FreedomRewriteConfig struct {
+	Domains  *cfgcommon.StringList `json:"domain"`
+	Port     *cfgcommon.PortList   `json:"port"`
+	Redirect string                `json:"redirect"`
+}
+
+func parseRedirect(redirect string) (*protocol.ServerEndpoint, error) {
+	host, portStr, err := net.SplitHostPort(redirect)
+	if err != nil {
+		return nil, newError("invalid redirect address: ", redirect, ": ", err).Base(err)
+	}
+	port, err := v2net.PortFromString(portStr)
+	if err != nil {
+		return nil, newError("invalid redirect port: ", redirect, ": ", err).Base(err)
+	}
+	server := &protocol.ServerEndpoint{
+		Port: uint32(port),
+	}
+	if len(host) > 0 {
+		server.Address = v2net.NewIPOrDomain(v2net.ParseAddress(host))
+	}
+	return server, nil
 }
 
 func (c *FreedomConfig) Build() (proto.Message, error) {
 	config := new(freedom.Config)
+	switch strings.ToLower(c.DomainStrategy) {
+	case "", "asis":
+		config.DomainStrategy = freedom.Config_AS_IS
+	case "useip":
+		config.DomainStrategy = freedom.Config_USE_IP
+	case "useip4", "useipv4":
+		config.DomainStrategy = freedom.Config_USE_IP4
+	case "useip6", "useipv6":
+		config.DomainStrategy = freedom.Config_USE_IP6
+	default:
+		return nil, newError("unsupported domain strategy: ", c.DomainStrategy)
+	}
+	config.FallbackDelay = c.FallbackDelay
+	if c.ProxyProtocol > 2 {
+		return nil, newError("unsupported PROXY protocol version: ", c.ProxyProtocol)
+	}
+	config.SendProxyProtocol = c.ProxyProtocol
 	if c.Timeout != nil {
 		config.Timeout = *c.Timeout
 	}
 	if len(c.Redirect) > 0 {
-		host, portStr, err := net.SplitHostPort(c.Redirect)
+		server, err := parseRedirect(c.Redirect)
 		if err != nil {
-			return nil, newError("invalid redirect address: ", c.Redirect, ": ", err).Base(err)
-		}
-		port, err := v2net.PortFromString(portStr)
-		if err != nil {
-			return nil, newError("invalid redirect port: ", c.Redirect, ": ", err).Base(err)
+			return nil, err
 		}
 		config.DestinationOverride = &freedom.DestinationOverride{
-			Server: &protocol.ServerEndpoint{
-				Port: uint32(port),
-			},
+			Server: server,
+		}
+	}
+	for _, r := range c.Rewrite {
+		rule, err := r.Build()
+		if err != nil {
+			return nil, err
 		}
-		if len(host) > 0 {
-			config.DestinationOverride.Server.Address = v2net.NewIPOrDomain(v2net.ParseAddress(host))
+		config.Rewrite = append(config.Rewrite, rule)
+	}
+	if c.Fragment != nil {
+		fragment, err := c.Fragment.Build()
+		if err != nil {
+			return nil, err
 		}
+		config.Fragment = fragment
 	}
 	return config, nil
 }
+
+func (c *FreedomFragmentConfig) Build() (*freedom.FragmentConfig, error) {
+	if len(c.Lengths) == 0 {
+		return nil, newError("fragment lengths are not specified")
+	}
+	for _, length := range c.Lengths {
+		if length == 0 {
+			return nil, newError("fragment length must be positive")
+		}
+	}
+	return &freedom.FragmentConfig{
+		Length: c.Lengths,
+		Delay:  c.Delay,
+	}, nil
+}
+
+func (c *FreedomRewriteConfig) Build() (*freedom.DestinationRewrite, error) {
+	if len(c.Redirect) == 0 {
+		return nil, newError("rewrite rule has no redirect")
+	}
+	server, err := parseRedirect(c.Redirect)
+	if err != nil {
+		return nil, err
+	}
+	if server.Address == nil && server.Port == 0 {
+		return nil, newError("rewrite rule redirects to nothing: ", c.Redirect)
+	}
+	rule := &freedom.DestinationRewrite{
+		Server: server,
+	}
+	if c.Domains != nil {
+		rule.Domain = []string(*c.Domains)
+	}
+	if c.Port != nil {
+		rule.Port = c.Port.Build()
+	}
+	return rule, nil
+}
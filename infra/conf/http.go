@@ -2,9 +2,11 @@ package conf
 
 import (
 	"encoding/json"
+	"strings"
 
 	"github.com/golang/protobuf/proto"
 
+	"github.com/vmessocket/vmessocket/common/net"
 	"github.com/vmessocket/vmessocket/common/protocol"
 	"github.com/vmessocket/vmessocket/common/serial"
 	"github.com/vmessocket/vmessocket/infra/conf/cfgcommon"
@@ -12,30 +14,52 @@ import (
 )
 
 type HTTPAccount struct {
-	Username string `json:"user"`
-	Password string `json:"pass"`
+	Username string            `json:"user"`
+	Password string            `json:"pass"`
+	Headers  map[string]string `json:"headers"`
 }
 
 type HTTPClientConfig struct {
-	Servers []*HTTPRemoteConfig `json:"servers"`
+	Servers     []*HTTPRemoteConfig `json:"servers"`
+	TLSSettings *HTTPTLSConfig      `json:"tlsSettings"`
+}
+
+type HTTPPacConfig struct {
+	Path         string                `json:"path"`
+	DirectDomain *cfgcommon.StringList `json:"direct"`
+	ProxyDomain  *cfgcommon.StringList `json:"proxy"`
+	Default      string                `json:"default"`
+	ProxyAddress string                `json:"proxyAddress"`
 }
 
 type HTTPRemoteConfig struct {
-	Address *cfgcommon.Address `json:"address"`
-	Port    uint16             `json:"port"`
-	Users   []json.RawMessage  `json:"users"`
+	Address     *cfgcommon.Address `json:"address"`
+	Port        uint16             `json:"port"`
+	Users       []json.RawMessage  `json:"users"`
+	TLSSettings *HTTPTLSConfig     `json:"tlsSettings"`
 }
 
 type HTTPServerConfig struct {
 	Timeout     uint32         `json:"timeout"`
 	Accounts    []*HTTPAccount `json:"accounts"`
 	Transparent bool           `json:"allowTransparent"`
+	Pac         *HTTPPacConfig `json:"pac"`
+	ConnectOnly bool           `json:"connectOnly"`
+}
+
+type HTTPTLSConfig struct {
+	ServerName    string                `json:"serverName"`
+	AllowInsecure bool                  `json:"allowInsecure"`
+	CAFile        string                `json:"certificateAuthorityFile"`
+	CA            []string              `json:"certificateAuthority"`
+	ALPN          *cfgcommon.StringList `json:"alpn"`
 }
 
 func (v *HTTPAccount) Build() *http.Account {
 	return &http.Account{
 		Username: v.Username,
 		Password: v.Password,
+		Header:   v.Headers,
 	}
 }
 
@@ -60,6 +84,45 @@ func (v *HTTPClientConfig) Build() (proto.Message, error) {
 			server.User = append(server.User, user)
 		}
 		config.Server[idx] = server
+		if serverConfig.TLSSettings != nil {
+			tlsSettings, err := serverConfig.TLSSettings.Build()
+			if err != nil {
+				return nil, newError("failed to build HTTP TLS settings for server ", idx).Base(err)
+			}
+			if config.ServerTlsSettings == nil {
+				config.ServerTlsSettings = make(map[string]*http.TLSConfig)
+			}
+			config.ServerTlsSettings[net.TCPDestination(server.Address.AsAddress(), net.Port(server.Port)).NetAddr()] = tlsSettings
+		}
+	}
+	if v.TLSSettings != nil {
+		tlsSettings, err := v.TLSSettings.Build()
+		if err != nil {
+			return nil, newError("failed to build HTTP TLS settings").Base(err)
+		}
+		config.TlsSettings = tlsSettings
+	}
+	return config, nil
+}
+
+func (c *HTTPPacConfig) Build() (*http.PacConfig, error) {
+	config := &http.PacConfig{
+		Path:         c.Path,
+		ProxyAddress: c.ProxyAddress,
+	}
+	if c.DirectDomain != nil {
+		config.DirectDomain = []string(*c.DirectDomain)
+	}
+	if c.ProxyDomain != nil {
+		config.ProxyDomain = []string(*c.ProxyDomain)
+	}
+	switch strings.ToLower(c.Default) {
+	case "", "proxy":
+		config.DefaultDirect = false
+	case "direct":
+		config.DefaultDirect = true
+	default:
+		return nil, newError("unknown PAC default action: ", c.Default)
 	}
 	return config, nil
 }
@@ -68,6 +131,14 @@ func (c *HTTPServerConfig) Build() (proto.Message, error) {
 	config := &http.ServerConfig{
 		Timeout:          c.Timeout,
 		AllowTransparent: c.Transparent,
+		ConnectOnly:      c.ConnectOnly,
+	}
+	if c.Pac != nil {
+		pac, err := c.Pac.Build()
+		if err != nil {
+			return nil, newError("failed to build PAC config").Base(err)
+		}
+		config.Pac = pac
 	}
 	if len(c.Accounts) > 0 {
 		config.Accounts = make(map[string]string)
@@ -77,3 +148,21 @@ func (c *HTTPServerConfig) Build() (proto.Message, error) {
 	}
 	return config, nil
 }
+
+func (c *HTTPTLSConfig) Build() (*http.TLSConfig, error) {
+	config := &http.TLSConfig{
+		ServerName:    c.ServerName,
+		AllowInsecure: c.AllowInsecure,
+	}
+	if len(c.CAFile) > 0 || len(c.CA) > 0 {
+		ca, err := readFileOrString(c.CAFile, c.CA)
+		if err != nil {
+			return nil, newError("failed to read certificate authority").Base(err)
+		}
+		config.CertificateAuthority = append(config.CertificateAuthority, ca)
+	}
+	if c.ALPN != nil {
+		config.NextProtocol = []string(*c.ALPN)
+	}
+	return config, nil
+}
@@ -0,0 +1,19 @@
+package conf
+
+import (
+	"github.com/golang/protobuf/proto"
+
+	"github.com/vmessocket/vmessocket/proxy/loopback"
+)
+
+type LoopbackConfig struct {
+	InboundTag string `json:"inboundTag"`
+	MaxHops    uint32 `json:"maxHops"`
+}
+
+func (c *LoopbackConfig) Build() (proto.Message, error) {
+	return &loopback.Config{
+		InboundTag: c.InboundTag,
+		MaxHops:    c.MaxHops,
+	}, nil
+}
@@ -0,0 +1,48 @@
+package conf
+
+import (
+	"github.com/vmessocket/vmessocket/app/dispatcher"
+	"github.com/vmessocket/vmessocket/infra/conf/cfgcommon"
+)
+
+type RoutingConfig struct {
+	Rules []*RoutingRuleConfig `json:"rules"`
+}
+
+type RoutingRuleConfig struct {
+	InboundTag  *cfgcommon.StringList `json:"inboundTag"`
+	User        *cfgcommon.StringList `json:"user"`
+	Domain      *cfgcommon.StringList `json:"domain"`
+	OutboundTag string                `json:"outboundTag"`
+}
+
+func (c *RoutingConfig) Build() (*dispatcher.Config, error) {
+	config := new(dispatcher.Config)
+	for idx, r := range c.Rules {
+		rule, err := r.Build()
+		if err != nil {
+			return nil, newError("failed to build routing rule ", idx).Base(err)
+		}
+		config.Rule = append(config.Rule, rule)
+	}
+	return config, nil
+}
+
+func (c *RoutingRuleConfig) Build() (*dispatcher.RoutingRule, error) {
+	if len(c.OutboundTag) == 0 {
+		return nil, newError("outboundTag is not specified")
+	}
+	rule := &dispatcher.RoutingRule{
+		OutboundTag: c.OutboundTag,
+	}
+	if c.InboundTag != nil {
+		rule.InboundTag = []string(*c.InboundTag)
+	}
+	if c.User != nil {
+		rule.UserEmail = []string(*c.User)
+	}
+	if c.Domain != nil {
+		rule.Domain = []string(*c.Domain)
+	}
+	return rule, nil
+}
@@ -0,0 +1,174 @@
+package conf
+
+import (
+	"sort"
+
+	"github.com/golang/protobuf/proto"
+
+	"github.com/vmessocket/vmessocket/infra/conf/cfgcommon"
+	"github.com/vmessocket/vmessocket/transport/internet/headers/http"
+	"github.com/vmessocket/vmessocket/transport/internet/headers/noop"
+)
+
+var tcpHeaderLoader = NewJSONConfigLoader(ConfigCreatorCache{
+	"none": func() interface{} { return new(NoOpConnectionAuthenticator) },
+	"http": func() interface{} { return new(Authenticator) },
+}, "type", "")
+
+type Authenticator struct {
+	Request  AuthenticatorRequest  `json:"request"`
+	Response AuthenticatorResponse `json:"response"`
+}
+
+type AuthenticatorRequest struct {
+	Version string                           `json:"version"`
+	Method  string                           `json:"method"`
+	Path    cfgcommon.StringList             `json:"path"`
+	Headers map[string]*cfgcommon.StringList `json:"headers"`
+}
+
+type AuthenticatorResponse struct {
+	Version string                           `json:"version"`
+	Status  string                           `json:"status"`
+	Reason  string                           `json:"reason"`
+	Headers map[string]*cfgcommon.StringList `json:"headers"`
+}
+
+type NoOpConnectionAuthenticator struct{}
+
+func buildHeaders(headers map[string]*cfgcommon.StringList) ([]*http.Header, error) {
+	headerNames := make([]string, 0, len(headers))
+	for key := range headers {
+		headerNames = append(headerNames, key)
+	}
+	sort.Strings(headerNames)
+	result := make([]*http.Header, 0, len(headers))
+	for _, key := range headerNames {
+		value := headers[key]
+		if value == nil || value.Len() == 0 {
+			return nil, newError("empty value for header ", key)
+		}
+		result = append(result, &http.Header{
+			Name:  key,
+			Value: append([]string(nil), (*value)...),
+		})
+	}
+	return result, nil
+}
+
+func (v *Authenticator) Build() (proto.Message, error) {
+	config := new(http.Config)
+	requestConfig, err := v.Request.Build()
+	if err != nil {
+		return nil, err
+	}
+	config.Request = requestConfig
+	responseConfig, err := v.Response.Build()
+	if err != nil {
+		return nil, err
+	}
+	config.Response = responseConfig
+	return config, nil
+}
+
+func (v *AuthenticatorRequest) Build() (*http.RequestConfig, error) {
+	config := &http.RequestConfig{
+		Uri: []string{"/"},
+		Header: []*http.Header{
+			{
+				Name:  "Host",
+				Value: []string{"www.baidu.com", "www.bing.com"},
+			},
+			{
+				Name: "User-Agent",
+				Value: []string{
+					"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/53.0.2785.143 Safari/537.36",
+					"Mozilla/5.0 (iPhone; CPU iPhone OS 10_0_2 like Mac OS X) AppleWebKit/601.1 (KHTML, like Gecko) CriOS/53.0.2785.109 Mobile/14A456 Safari/601.1.46",
+				},
+			},
+			{
+				Name:  "Accept-Encoding",
+				Value: []string{"gzip, deflate"},
+			},
+			{
+				Name:  "Connection",
+				Value: []string{"keep-alive"},
+			},
+			{
+				Name:  "Pragma",
+				Value: []string{"no-cache"},
+			},
+		},
+	}
+	if len(v.Version) > 0 {
+		config.Version = &http.Version{Value: v.Version}
+	}
+	if len(v.Method) > 0 {
+		config.Method = &http.Method{Value: v.Method}
+	}
+	if len(v.Path) > 0 {
+		config.Uri = append([]string(nil), v.Path...)
+	}
+	if len(v.Headers) > 0 {
+		headers, err := buildHeaders(v.Headers)
+		if err != nil {
+			return nil, err
+		}
+		config.Header = headers
+	}
+	return config, nil
+}
+
+func (v *AuthenticatorResponse) Build() (*http.ResponseConfig, error) {
+	config := &http.ResponseConfig{
+		Header: []*http.Header{
+			{
+				Name:  "Content-Type",
+				Value: []string{"application/octet-stream", "video/mpeg"},
+			},
+			{
+				Name:  "Transfer-Encoding",
+				Value: []string{"chunked"},
+			},
+			{
+				Name:  "Connection",
+				Value: []string{"keep-alive"},
+			},
+			{
+				Name:  "Pragma",
+				Value: []string{"no-cache"},
+			},
+			{
+				Name:  "Cache-Control",
+				Value: []string{"private", "no-cache"},
+			},
+		},
+	}
+	if len(v.Version) > 0 {
+		config.Version = &http.Version{Value: v.Version}
+	}
+	if len(v.Status) > 0 || len(v.Reason) > 0 {
+		config.Status = &http.Status{
+			Code:   "200",
+			Reason: "OK",
+		}
+		if len(v.Status) > 0 {
+			config.Status.Code = v.Status
+		}
+		if len(v.Reason) > 0 {
+			config.Status.Reason = v.Reason
+		}
+	}
+	if len(v.Headers) > 0 {
+		headers, err := buildHeaders(v.Headers)
+		if err != nil {
+			return nil, err
+		}
+		config.Header = headers
+	}
+	return config, nil
+}
+
+func (NoOpConnectionAuthenticator) Build() (proto.Message, error) {
+	return new(noop.ConnectionConfig), nil
+}
@@ -1,18 +1,40 @@
 package conf
 
 import (
+	"crypto/x509"
+	"encoding/base64"
 	"encoding/json"
 	"strings"
 
 	"github.com/golang/protobuf/proto"
 
 	"github.com/vmessocket/vmessocket/common/platform/filesystem"
+	http_proto "github.com/vmessocket/vmessocket/common/protocol/http"
+	"github.com/vmessocket/vmessocket/common/protocol/tls/cert"
 	"github.com/vmessocket/vmessocket/common/serial"
+	"github.com/vmessocket/vmessocket/infra/conf/cfgcommon"
 	"github.com/vmessocket/vmessocket/transport/internet"
+	"github.com/vmessocket/vmessocket/transport/internet/grpc"
+	"github.com/vmessocket/vmessocket/transport/internet/http"
 	"github.com/vmessocket/vmessocket/transport/internet/tcp"
+	"github.com/vmessocket/vmessocket/transport/internet/tls"
 	"github.com/vmessocket/vmessocket/transport/internet/websocket"
 )
 
+type GRPCConfig struct {
+	Host        string `json:"host"`
+	ServiceName string `json:"serviceName"`
+	MultiMode   bool   `json:"multiMode"`
+}
+
+type HTTPConfig struct {
+	Host           *cfgcommon.StringList `json:"host"`
+	Path           string                `json:"path"`
+	Method         string                `json:"method"`
+	Headers        map[string]string     `json:"headers"`
+	TrustedProxies []string              `json:"trustedProxies"`
+}
+
 type ProxyConfig struct {
 	TransportLayerProxy bool   `json:"transportLayer"`
 }
@@ -20,27 +42,61 @@ type ProxyConfig struct {
 type StreamConfig struct {
 	Network        *TransportProtocol `json:"network"`
 	Security       string             `json:"security"`
+	TLSSettings    *TLSConfig         `json:"tlsSettings"`
 	TCPSettings    *TCPConfig         `json:"tcpSettings"`
 	WSSettings     *WebSocketConfig   `json:"wsSettings"`
+	HTTPSettings   *HTTPConfig        `json:"httpSettings"`
+	GRPCSettings   *GRPCConfig        `json:"grpcSettings"`
 }
 
 type TCPConfig struct {
-	HeaderConfig json.RawMessage `json:"header"`
+	HeaderConfig        json.RawMessage `json:"header"`
+	AcceptProxyProtocol bool            `json:"acceptProxyProtocol"`
+	SendProxyProtocol   uint32          `json:"sendProxyProtocol"`
 }
 
 type TLSCertConfig struct {
-	CertFile string   `json:"certificateFile"`
-	CertStr  []string `json:"certificate"`
-	KeyFile  string   `json:"keyFile"`
-	KeyStr   []string `json:"key"`
-	Usage    string   `json:"usage"`
+	CertFile     string                `json:"certificateFile"`
+	CertStr      []string              `json:"certificate"`
+	KeyFile      string                `json:"keyFile"`
+	KeyStr       []string              `json:"key"`
+	Usage        string                `json:"usage"`
+	IssueDomains *cfgcommon.StringList `json:"issueDomains"`
+}
+
+type TLSConfig struct {
+	Insecure                         bool                  `json:"allowInsecure"`
+	Certs                            []*TLSCertConfig      `json:"certificates"`
+	ServerName                       string                `json:"serverName"`
+	ALPN                             *cfgcommon.StringList `json:"alpn"`
+	EnableSessionResumption          bool                  `json:"enableSessionResumption"`
+	DisableSystemRoot                bool                  `json:"disableSystemRoot"`
+	MinVersion                       string                `json:"minVersion"`
+	MaxVersion                       string                `json:"maxVersion"`
+	CipherSuites                     string                `json:"cipherSuites"`
+	PreferServerCipherSuites         bool                  `json:"preferServerCipherSuites"`
+	PinnedPeerCertificateChainSha256 []string              `json:"pinnedPeerCertificateChainSha256"`
 }
 
 type TransportProtocol string
 
 type WebSocketConfig struct {
-	Path    string            `json:"path"`
-	Headers map[string]string `json:"headers"`
+	Path                string             `json:"path"`
+	Headers             map[string]string  `json:"headers"`
+	MaxEarlyData        int32              `json:"maxEarlyData"`
+	EarlyDataHeaderName string             `json:"earlyDataHeaderName"`
+	Fallback            *WebSocketFallback `json:"fallback"`
+	Host                string             `json:"host"`
+	AllowedOrigins      []string           `json:"allowedOrigins"`
+	TrustedProxies      []string           `json:"trustedProxies"`
+	AcceptProxyProtocol bool               `json:"acceptProxyProtocol"`
+}
+
+type WebSocketFallback struct {
+	Status      uint32 `json:"status"`
+	Content     string `json:"content"`
+	ContentType string `json:"contentType"`
+	Directory   string `json:"directory"`
 }
 
 func readFileOrString(f string, s []string) ([]byte, error) {
@@ -53,6 +109,35 @@ func readFileOrString(f string, s []string) ([]byte, error) {
 	return nil, newError("both file and bytes are empty.")
 }
 
+func (c *GRPCConfig) Build() (proto.Message, error) {
+	return &grpc.Config{
+		Host:        c.Host,
+		ServiceName: c.ServiceName,
+		MultiMode:   c.MultiMode,
+	}, nil
+}
+
+func (c *HTTPConfig) Build() (proto.Message, error) {
+	if _, err := http_proto.ParseTrustedProxies(c.TrustedProxies); err != nil {
+		return nil, err
+	}
+	config := &http.Config{
+		Path:           c.Path,
+		Method:         c.Method,
+		TrustedProxies: c.TrustedProxies,
+	}
+	if c.Host != nil {
+		config.Host = []string(*c.Host)
+	}
+	for key, value := range c.Headers {
+		config.Header = append(config.Header, &http.Header{
+			Key:   key,
+			Value: value,
+		})
+	}
+	return config, nil
+}
+
 func (c *StreamConfig) Build() (*internet.StreamConfig, error) {
 	config := &internet.StreamConfig{
 		ProtocolName: "tcp",
@@ -64,6 +149,23 @@ func (c *StreamConfig) Build() (*internet.StreamConfig, error) {
 		}
 		config.ProtocolName = protocol
 	}
+	switch strings.ToLower(c.Security) {
+	case "", "none":
+	case "tls":
+		tlsSettings := c.TLSSettings
+		if tlsSettings == nil {
+			tlsSettings = &TLSConfig{}
+		}
+		ts, err := tlsSettings.Build()
+		if err != nil {
+			return nil, newError("Failed to build TLS config.").Base(err)
+		}
+		tm := serial.ToTypedMessage(ts)
+		config.SecuritySettings = append(config.SecuritySettings, tm)
+		config.SecurityType = tm.Type
+	default:
+		return nil, newError("unknown security type: ", c.Security)
+	}
 	if c.TCPSettings != nil {
 		ts, err := c.TCPSettings.Build()
 		if err != nil {
@@ -84,11 +186,139 @@ func (c *StreamConfig) Build() (*internet.StreamConfig, error) {
 			Settings:     serial.ToTypedMessage(ts),
 		})
 	}
+	if c.HTTPSettings != nil {
+		ts, err := c.HTTPSettings.Build()
+		if err != nil {
+			return nil, newError("Failed to build HTTP config.").Base(err)
+		}
+		config.TransportSettings = append(config.TransportSettings, &internet.TransportConfig{
+			ProtocolName: "http",
+			Settings:     serial.ToTypedMessage(ts),
+		})
+	}
+	if c.GRPCSettings != nil {
+		ts, err := c.GRPCSettings.Build()
+		if err != nil {
+			return nil, newError("Failed to build gRPC config.").Base(err)
+		}
+		config.TransportSettings = append(config.TransportSettings, &internet.TransportConfig{
+			ProtocolName: "grpc",
+			Settings:     serial.ToTypedMessage(ts),
+		})
+	}
 	return config, nil
 }
 
 func (c *TCPConfig) Build() (proto.Message, error) {
-	config := new(tcp.Config)
+	if c.SendProxyProtocol > 2 {
+		return nil, newError("unsupported PROXY protocol version: ", c.SendProxyProtocol)
+	}
+	config := &tcp.Config{
+		AcceptProxyProtocol: c.AcceptProxyProtocol,
+		SendProxyProtocol:   c.SendProxyProtocol,
+	}
+	if len(c.HeaderConfig) > 0 {
+		headerConfig, _, err := tcpHeaderLoader.Load(c.HeaderConfig)
+		if err != nil {
+			return nil, newError("invalid TCP header config").Base(err).AtError()
+		}
+		ts, err := headerConfig.(Buildable).Build()
+		if err != nil {
+			return nil, newError("invalid TCP header config").Base(err).AtError()
+		}
+		config.HeaderSettings = serial.ToTypedMessage(ts)
+	}
+	return config, nil
+}
+
+func (c *TLSCertConfig) Build() (*tls.Certificate, error) {
+	certificate := new(tls.Certificate)
+	certPEM, err := readFileOrString(c.CertFile, c.CertStr)
+	if err != nil {
+		return nil, newError("failed to parse certificate").Base(err)
+	}
+	certificate.Certificate = certPEM
+	switch strings.ToLower(c.Usage) {
+	case "", "encipherment":
+		certificate.Usage = tls.Certificate_ENCIPHERMENT
+	case "verify":
+		certificate.Usage = tls.Certificate_AUTHORITY_VERIFY
+	case "issue":
+		certificate.Usage = tls.Certificate_AUTHORITY_ISSUE
+	default:
+		return nil, newError("unknown certificate usage: ", c.Usage)
+	}
+	if len(c.KeyFile) > 0 || len(c.KeyStr) > 0 {
+		key, err := readFileOrString(c.KeyFile, c.KeyStr)
+		if err != nil {
+			return nil, newError("failed to parse key").Base(err)
+		}
+		certificate.Key = key
+		if len(c.CertFile) > 0 && len(c.KeyFile) > 0 {
+			certificate.CertificateFile = c.CertFile
+			certificate.KeyFile = c.KeyFile
+		}
+	} else if certificate.Usage != tls.Certificate_AUTHORITY_VERIFY {
+		return nil, newError("key is not set for certificate")
+	}
+	if certificate.Usage == tls.Certificate_AUTHORITY_ISSUE {
+		authority, err := cert.ParseCertificate(certificate.Certificate, certificate.Key)
+		if err != nil {
+			return nil, newError("failed to parse issuing authority").Base(err)
+		}
+		parsed, err := x509.ParseCertificate(authority.Certificate)
+		if err != nil {
+			return nil, newError("failed to parse issuing authority").Base(err)
+		}
+		if !parsed.IsCA {
+			return nil, newError("certificate for issuing is not a CA: ", parsed.Subject.CommonName)
+		}
+		if c.IssueDomains == nil || len(*c.IssueDomains) == 0 {
+			return nil, newError("issueDomains is not set for issuing certificate")
+		}
+		certificate.IssueDomain = []string(*c.IssueDomains)
+	}
+	return certificate, nil
+}
+
+func (c *TLSConfig) Build() (proto.Message, error) {
+	config := &tls.Config{
+		AllowInsecure:            c.Insecure,
+		ServerName:               c.ServerName,
+		EnableSessionResumption:  c.EnableSessionResumption,
+		DisableSystemRoot:        c.DisableSystemRoot,
+		MinVersion:               c.MinVersion,
+		MaxVersion:               c.MaxVersion,
+		CipherSuites:             c.CipherSuites,
+		PreferServerCipherSuites: c.PreferServerCipherSuites,
+	}
+	config.Certificate = make([]*tls.Certificate, len(c.Certs))
+	for idx, certConf := range c.Certs {
+		cert, err := certConf.Build()
+		if err != nil {
+			return nil, newError("Failed to build TLS certificate.").Base(err)
+		}
+		config.Certificate[idx] = cert
+	}
+	if c.ALPN != nil && len(*c.ALPN) > 0 {
+		config.NextProtocol = []string(*c.ALPN)
+	}
+	if _, err := tls.ParseVersion(c.MinVersion); err != nil {
+		return nil, err
+	}
+	if _, err := tls.ParseVersion(c.MaxVersion); err != nil {
+		return nil, err
+	}
+	if _, err := tls.ParseCipherSuites(c.CipherSuites); err != nil {
+		return nil, err
+	}
+	for _, v := range c.PinnedPeerCertificateChainSha256 {
+		hashValue, err := base64.StdEncoding.DecodeString(v)
+		if err != nil {
+			return nil, newError("invalid pinned certificate chain hash: ", v).Base(err)
+		}
+		config.PinnedPeerCertificateChainSha256 = append(config.PinnedPeerCertificateChainSha256, hashValue)
+	}
 	return config, nil
 }
 
@@ -100,6 +330,8 @@ func (p TransportProtocol) Build() (string, error) {
 		return "websocket", nil
 	case "h2", "http":
 		return "http", nil
+	case "grpc", "gun":
+		return "grpc", nil
 	default:
 		return "", newError("Config: unknown transport protocol: ", p)
 	}
@@ -114,9 +346,43 @@ func (c *WebSocketConfig) Build() (proto.Message, error) {
 			Value: value,
 		})
 	}
+	if c.MaxEarlyData < 0 {
+		return nil, newError("invalid maxEarlyData: ", c.MaxEarlyData)
+	}
+	if _, err := http_proto.ParseTrustedProxies(c.TrustedProxies); err != nil {
+		return nil, err
+	}
 	config := &websocket.Config{
-		Path:   path,
-		Header: header,
+		Path:                path,
+		Header:              header,
+		MaxEarlyData:        c.MaxEarlyData,
+		EarlyDataHeaderName: c.EarlyDataHeaderName,
+		Host:                c.Host,
+		AllowedOrigins:      c.AllowedOrigins,
+		TrustedProxies:      c.TrustedProxies,
+		AcceptProxyProtocol: c.AcceptProxyProtocol,
+	}
+	if c.Fallback != nil {
+		fallback, err := c.Fallback.Build()
+		if err != nil {
+			return nil, newError("invalid WebSocket fallback").Base(err)
+		}
+		config.Fallback = fallback
 	}
 	return config, nil
 }
+
+func (c *WebSocketFallback) Build() (*websocket.Fallback, error) {
+	if len(c.Content) > 0 && len(c.Directory) > 0 {
+		return nil, newError("content and directory are mutually exclusive")
+	}
+	if c.Status != 0 && (c.Status < 100 || c.Status > 599) {
+		return nil, newError("invalid status code: ", c.Status)
+	}
+	return &websocket.Fallback{
+		Status:      c.Status,
+		Content:     c.Content,
+		ContentType: c.ContentType,
+		Directory:   c.Directory,
+	}, nil
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/golang/protobuf/proto"
 
+	"github.com/vmessocket/vmessocket/common/net"
 	"github.com/vmessocket/vmessocket/common/protocol"
 	"github.com/vmessocket/vmessocket/common/serial"
 	"github.com/vmessocket/vmessocket/infra/conf/cfgcommon"
@@ -26,14 +27,36 @@ type VMessDefaultConfig struct {
 	Level    byte   `json:"level"`
 }
 
+type VMessDetourConfig struct {
+	ToTag string `json:"to"`
+}
+
+type VMessFallbackConfig struct {
+	Dest string `json:"dest"`
+}
+
 type VMessInboundConfig struct {
-	Users        []json.RawMessage   `json:"clients"`
-	Defaults     *VMessDefaultConfig `json:"default"`
-	SecureOnly   bool                `json:"disableInsecureEncryption"`
+	Users               []json.RawMessage    `json:"clients"`
+	Defaults            *VMessDefaultConfig  `json:"default"`
+	Detours             *VMessDetourConfig   `json:"detour"`
+	SecureOnly          bool                 `json:"disableInsecureEncryption"`
+	Fallback            *VMessFallbackConfig `json:"fallback"`
+	UserFile            *VMessUserFileConfig `json:"userFile"`
+	NoLegacy            bool                 `json:"disableLegacy"`
+	AEAD                string               `json:"aead"`
+	AllowedSecurity     []string             `json:"allowedSecurity"`
+	NoTerminationSignal bool                 `json:"noTerminationSignal"`
 }
 
 type VMessOutboundConfig struct {
-	Receivers []*VMessOutboundTarget `json:"vnext"`
+	Receivers           []*VMessOutboundTarget `json:"vnext"`
+	AEAD                string                 `json:"aead"`
+	GlobalPadding       *bool                  `json:"globalPadding"`
+	AuthenticatedLength bool                   `json:"authenticatedLength"`
+	NoTerminationSignal bool                   `json:"noTerminationSignal"`
+	Strategy            string                 `json:"strategy"`
+	MaxFailures         uint32                 `json:"maxFailures"`
+	EjectDuration       uint32                 `json:"ejectDuration"`
 }
 
 type VMessOutboundTarget struct {
@@ -42,6 +65,30 @@ type VMessOutboundTarget struct {
 	Users   []json.RawMessage  `json:"users"`
 }
 
+type VMessUserFileConfig struct {
+	Path     string `json:"path"`
+	Interval uint32 `json:"interval"`
+}
+
+func parseVMessAllowedSecurity(s []string) ([]protocol.SecurityType, error) {
+	types := make([]protocol.SecurityType, 0, len(s))
+	for _, t := range s {
+		switch strings.ToLower(t) {
+		case "aes-128-gcm":
+			types = append(types, protocol.SecurityType_AES128_GCM)
+		case "chacha20-poly1305":
+			types = append(types, protocol.SecurityType_CHACHA20_POLY1305)
+		case "none":
+			types = append(types, protocol.SecurityType_NONE)
+		case "legacy", "aes-128-cfb":
+			types = append(types, protocol.SecurityType_LEGACY)
+		default:
+			return nil, newError("unsupported VMess security type: ", t)
+		}
+	}
+	return types, nil
+}
+
 func (a *VMessAccount) Build() *vmess.Account {
 	var st protocol.SecurityType
 	switch strings.ToLower(a.Security) {
@@ -75,13 +122,77 @@ func (c *VMessDefaultConfig) Build() *inbound.DefaultConfig {
 	return config
 }
 
+func (c *VMessDetourConfig) Build() *inbound.DetourConfig {
+	return &inbound.DetourConfig{
+		To: c.ToTag,
+	}
+}
+
+func (c *VMessFallbackConfig) Build() (*net.Endpoint, error) {
+	if c.Dest == "" {
+		return nil, newError("VMess fallback destination is not set")
+	}
+	dest, err := net.ParseDestination(c.Dest)
+	if err != nil {
+		return nil, newError("invalid VMess fallback destination: ", c.Dest).Base(err)
+	}
+	switch dest.Network {
+	case net.Network_Unknown:
+		dest.Network = net.Network_TCP
+	case net.Network_TCP, net.Network_UNIX:
+	default:
+		return nil, newError("unsupported VMess fallback network: ", dest.Network)
+	}
+	if dest.Network == net.Network_TCP && dest.Port == 0 {
+		return nil, newError("VMess fallback destination has no port: ", c.Dest)
+	}
+	return &net.Endpoint{
+		Network: dest.Network,
+		Address: net.NewIPOrDomain(dest.Address),
+		Port:    uint32(dest.Port),
+	}, nil
+}
+
 func (c *VMessInboundConfig) Build() (proto.Message, error) {
 	config := &inbound.Config{
 		SecureEncryptionOnly: c.SecureOnly,
+		DisableLegacy:        c.NoLegacy,
+		NoTerminationSignal:  c.NoTerminationSignal,
+	}
+	switch strings.ToLower(c.AEAD) {
+	case "":
+	case "forced":
+		config.AeadMode = inbound.AEADMode_AEAD_MODE_FORCED
+	case "optional":
+		config.AeadMode = inbound.AEADMode_AEAD_MODE_OPTIONAL
+	default:
+		return nil, newError("unknown VMess inbound AEAD mode: ", c.AEAD)
 	}
+	allowedSecurity, err := parseVMessAllowedSecurity(c.AllowedSecurity)
+	if err != nil {
+		return nil, err
+	}
+	config.AllowedSecurity = allowedSecurity
 	if c.Defaults != nil {
 		config.Default = c.Defaults.Build()
 	}
+	if c.Detours != nil {
+		config.Detour = c.Detours.Build()
+	}
+	if c.Fallback != nil {
+		fallback, err := c.Fallback.Build()
+		if err != nil {
+			return nil, err
+		}
+		config.Fallback = fallback
+	}
+	if c.UserFile != nil {
+		userFile, err := c.UserFile.Build()
+		if err != nil {
+			return nil, err
+		}
+		config.UserFile = userFile
+	}
 	config.User = make([]*protocol.User, len(c.Users))
 	for idx, rawData := range c.Users {
 		user := new(protocol.User)
@@ -99,7 +210,40 @@ func (c *VMessInboundConfig) Build() (proto.Message, error) {
 }
 
 func (c *VMessOutboundConfig) Build() (proto.Message, error) {
-	config := new(outbound.Config)
+	config := &outbound.Config{
+		AuthenticatedLength: c.AuthenticatedLength,
+		NoTerminationSignal: c.NoTerminationSignal,
+		MaxFailures:         c.MaxFailures,
+		EjectDuration:       c.EjectDuration,
+	}
+	switch strings.ToLower(c.Strategy) {
+	case "", "roundrobin":
+		config.Strategy = outbound.ServerStrategy_ROUND_ROBIN
+	case "random":
+		config.Strategy = outbound.ServerStrategy_RANDOM
+	case "leastfailures":
+		config.Strategy = outbound.ServerStrategy_LEAST_FAILURES
+	case "lowestrtt":
+		config.Strategy = outbound.ServerStrategy_LOWEST_RTT
+	default:
+		return nil, newError("unknown VMess server strategy: ", c.Strategy)
+	}
+	if c.GlobalPadding != nil {
+		if *c.GlobalPadding {
+			config.PaddingMode = outbound.PaddingMode_PADDING_MODE_ENABLED
+		} else {
+			config.PaddingMode = outbound.PaddingMode_PADDING_MODE_DISABLED
+		}
+	}
+	switch strings.ToLower(c.AEAD) {
+	case "":
+	case "forced":
+		config.AeadMode = outbound.AEADMode_AEAD_MODE_FORCED
+	case "disabled":
+		config.AeadMode = outbound.AEADMode_AEAD_MODE_DISABLED
+	default:
+		return nil, newError("unknown VMess outbound AEAD mode: ", c.AEAD)
+	}
 	if len(c.Receivers) == 0 {
 		return nil, newError("0 VMess receiver configured")
 	}
@@ -132,3 +276,13 @@ func (c *VMessOutboundConfig) Build() (proto.Message, error) {
 	config.Receiver = serverSpecs
 	return config, nil
 }
+
+func (c *VMessUserFileConfig) Build() (*inbound.UserFile, error) {
+	if c.Path == "" {
+		return nil, newError("VMess user file path is not set")
+	}
+	return &inbound.UserFile{
+		Path:     c.Path,
+		Interval: c.Interval,
+	}, nil
+}
@@ -0,0 +1,122 @@
+package conf_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+
+	"github.com/vmessocket/vmessocket/common/protocol"
+	. "github.com/vmessocket/vmessocket/infra/conf"
+	"github.com/vmessocket/vmessocket/proxy/vmess/inbound"
+	"github.com/vmessocket/vmessocket/proxy/vmess/outbound"
+)
+
+func TestVMessInboundConfigBuild(t *testing.T) {
+	testCases := []struct {
+		name   string
+		input  string
+		expect *inbound.Config
+		err    bool
+	}{
+		{
+			name:  "defaults",
+			input: `{}`,
+			expect: &inbound.Config{
+				User:            []*protocol.User{},
+				AllowedSecurity: []protocol.SecurityType{},
+			},
+		},
+		{
+			name:  "optional aead with allowed security",
+			input: `{"aead": "optional", "allowedSecurity": ["aes-128-gcm", "none", "aes-128-cfb"], "noTerminationSignal": true}`,
+			expect: &inbound.Config{
+				User:                []*protocol.User{},
+				AeadMode:            inbound.AEADMode_AEAD_MODE_OPTIONAL,
+				AllowedSecurity:     []protocol.SecurityType{protocol.SecurityType_AES128_GCM, protocol.SecurityType_NONE, protocol.SecurityType_LEGACY},
+				NoTerminationSignal: true,
+			},
+		},
+		{
+			name:  "zero security",
+			input: `{"allowedSecurity": ["zero"]}`,
+			err:   true,
+		},
+		{
+			name:  "auto security",
+			input: `{"allowedSecurity": ["auto"]}`,
+			err:   true,
+		},
+		{
+			name:  "unknown aead mode",
+			input: `{"aead": "disabled"}`,
+			err:   true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := new(VMessInboundConfig)
+			if err := json.Unmarshal([]byte(tc.input), config); err != nil {
+				t.Fatal(err)
+			}
+			message, err := config.Build()
+			if (err != nil) != tc.err {
+				t.Fatal("unexpected error: ", err)
+			}
+			if err == nil && !proto.Equal(message, tc.expect) {
+				t.Fatalf("expected %v, got %v", tc.expect, message)
+			}
+		})
+	}
+}
+
+func TestVMessOutboundConfigBuild(t *testing.T) {
+	const receivers = `"vnext": [{"address": "127.0.0.1", "port": 10086, "users": [{"id": "e55c8d17-2cf3-b21a-bcf1-eeacb011ed79"}]}]`
+	testCases := []struct {
+		name    string
+		input   string
+		padding outbound.PaddingMode
+		aead    outbound.AEADMode
+		err     bool
+	}{
+		{
+			name:  "defaults",
+			input: `{` + receivers + `}`,
+		},
+		{
+			name:    "padding enabled",
+			input:   `{"globalPadding": true, ` + receivers + `}`,
+			padding: outbound.PaddingMode_PADDING_MODE_ENABLED,
+		},
+		{
+			name:    "padding disabled",
+			input:   `{"globalPadding": false, "aead": "forced", ` + receivers + `}`,
+			padding: outbound.PaddingMode_PADDING_MODE_DISABLED,
+			aead:    outbound.AEADMode_AEAD_MODE_FORCED,
+		},
+		{
+			name:  "unknown aead mode",
+			input: `{"aead": "optional", ` + receivers + `}`,
+			err:   true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := new(VMessOutboundConfig)
+			if err := json.Unmarshal([]byte(tc.input), config); err != nil {
+				t.Fatal(err)
+			}
+			message, err := config.Build()
+			if (err != nil) != tc.err {
+				t.Fatal("unexpected error: ", err)
+			}
+			if err != nil {
+				return
+			}
+			built := message.(*outbound.Config)
+			if built.PaddingMode != tc.padding || built.AeadMode != tc.aead {
+				t.Fatalf("unexpected config %v", built)
+			}
+		})
+	}
+}
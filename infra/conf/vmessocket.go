@@ -19,10 +19,11 @@ var (
 		"vmess": func() interface{} { return new(VMessInboundConfig) },
 	}, "protocol", "settings")
 	outboundConfigLoader = NewJSONConfigLoader(ConfigCreatorCache{
-		"dns":     func() interface{} { return new(DNSOutboundConfig) },
-		"freedom": func() interface{} { return new(FreedomConfig) },
-		"http":    func() interface{} { return new(HTTPClientConfig) },
-		"vmess":   func() interface{} { return new(VMessOutboundConfig) },
+		"dns":      func() interface{} { return new(DNSOutboundConfig) },
+		"freedom":  func() interface{} { return new(FreedomConfig) },
+		"http":     func() interface{} { return new(HTTPClientConfig) },
+		"loopback": func() interface{} { return new(LoopbackConfig) },
+		"vmess":    func() interface{} { return new(VMessOutboundConfig) },
 	}, "protocol", "settings")
 	ctllog = log.New(os.Stderr, "v2ctl> ", 0)
 )
@@ -37,23 +38,49 @@ type Config struct {
 	InboundConfigs  []InboundDetourConfig       `json:"inbounds"`
 	OutboundConfigs []OutboundDetourConfig      `json:"outbounds"`
 	Transport       *TransportConfig            `json:"transport"`
+	RouterConfig    *RoutingConfig              `json:"routing"`
+	API             *APIConfig                  `json:"api"`
 	Services        map[string]*json.RawMessage `json:"services"`
 }
 
+type InboundDetourAllocationConfig struct {
+	Strategy    string  `json:"strategy"`
+	Concurrency *uint32 `json:"concurrency"`
+	RefreshMin  *uint32 `json:"refresh"`
+}
+
 type InboundDetourConfig struct {
-	Protocol       string                `json:"protocol"`
-	PortRange      *cfgcommon.PortRange  `json:"port"`
-	ListenOn       *cfgcommon.Address    `json:"listen"`
-	Settings       *json.RawMessage      `json:"settings"`
-	StreamSetting  *StreamConfig         `json:"streamSettings"`
-	DomainOverride *cfgcommon.StringList `json:"domainOverride"`
+	Protocol       string                         `json:"protocol"`
+	PortRange      *cfgcommon.PortRange           `json:"port"`
+	ListenOn       *cfgcommon.Address             `json:"listen"`
+	Settings       *json.RawMessage               `json:"settings"`
+	Tag            string                         `json:"tag"`
+	Allocation     *InboundDetourAllocationConfig `json:"allocate"`
+	StreamSetting  *StreamConfig                  `json:"streamSettings"`
+	DomainOverride *cfgcommon.StringList          `json:"domainOverride"`
+}
+
+type MuxBypassConfig struct {
+	Network *cfgcommon.NetworkList `json:"network"`
+	Port    *cfgcommon.PortList    `json:"port"`
+}
+
+type MuxConfig struct {
+	Enabled            bool               `json:"enabled"`
+	Concurrency        uint32             `json:"concurrency"`
+	MaxIdleConnections uint32             `json:"maxIdleConnections"`
+	IdleTimeout        uint32             `json:"idleTimeout"`
+	MaxLifetime        uint32             `json:"maxLifetime"`
+	Bypass             []*MuxBypassConfig `json:"bypass"`
 }
 
 type OutboundDetourConfig struct {
 	Protocol      string           `json:"protocol"`
+	Tag           string           `json:"tag"`
 	Settings      *json.RawMessage `json:"settings"`
 	StreamSetting *StreamConfig    `json:"streamSettings"`
 	ProxySettings *ProxyConfig     `json:"proxySettings"`
+	MuxSettings   *MuxConfig       `json:"mux"`
 }
 
 func applyTransportConfig(s *StreamConfig, t *TransportConfig) {
@@ -81,9 +108,17 @@ func toProtocolList(s []string) ([]proxyman.KnownProtocols, error) {
 }
 
 func (c *Config) Build() (*core.Config, error) {
+	dispatcherConfig := new(dispatcher.Config)
+	if c.RouterConfig != nil {
+		routing, err := c.RouterConfig.Build()
+		if err != nil {
+			return nil, newError("failed to build routing config").Base(err)
+		}
+		dispatcherConfig = routing
+	}
 	config := &core.Config{
 		App: []*serial.TypedMessage{
-			serial.ToTypedMessage(&dispatcher.Config{}),
+			serial.ToTypedMessage(dispatcherConfig),
 			serial.ToTypedMessage(&proxyman.InboundConfig{}),
 			serial.ToTypedMessage(&proxyman.OutboundConfig{}),
 		},
@@ -95,6 +130,13 @@ func (c *Config) Build() (*core.Config, error) {
 		logConfMsg = serial.ToTypedMessage(DefaultLogConfig())
 	}
 	config.App = append([]*serial.TypedMessage{logConfMsg}, config.App...)
+	if c.API != nil {
+		apiConf, err := c.API.Build()
+		if err != nil {
+			return nil, err
+		}
+		config.App = append(config.App, serial.ToTypedMessage(apiConf))
+	}
 	if msg, err := c.BuildServices(c.Services); err != nil {
 		return nil, newError("Cannot load service").Base(err)
 	} else {
@@ -155,6 +197,31 @@ func (c *Config) Build() (*core.Config, error) {
 	return config, nil
 }
 
+func (c *InboundDetourAllocationConfig) Build() (*proxyman.AllocationStrategy, error) {
+	config := new(proxyman.AllocationStrategy)
+	switch strings.ToLower(c.Strategy) {
+	case "", "always":
+		config.Type = proxyman.AllocationStrategy_Always
+	case "random":
+		config.Type = proxyman.AllocationStrategy_Random
+	case "external":
+		return nil, newError("allocation strategy \"external\" is not supported")
+	default:
+		return nil, newError("unknown allocation strategy: ", c.Strategy)
+	}
+	if c.Concurrency != nil {
+		config.Concurrency = &proxyman.AllocationStrategy_AllocationStrategyConcurrency{
+			Value: *c.Concurrency,
+		}
+	}
+	if c.RefreshMin != nil {
+		config.Refresh = &proxyman.AllocationStrategy_AllocationStrategyRefresh{
+			Value: *c.RefreshMin,
+		}
+	}
+	return config, nil
+}
+
 func (c *InboundDetourConfig) Build() (*core.InboundHandlerConfig, error) {
 	receiverSettings := &proxyman.ReceiverConfig{}
 	if c.ListenOn == nil {
@@ -180,6 +247,22 @@ func (c *InboundDetourConfig) Build() (*core.InboundHandlerConfig, error) {
 			return nil, newError("unable to listen on domain address: ", c.ListenOn.Domain())
 		}
 	}
+	if c.Allocation != nil {
+		as, err := c.Allocation.Build()
+		if err != nil {
+			return nil, err
+		}
+		if as.Type == proxyman.AllocationStrategy_Random {
+			if receiverSettings.PortRange == nil {
+				return nil, newError("random allocation requires a port range")
+			}
+			portRange := receiverSettings.PortRange.To - receiverSettings.PortRange.From + 1
+			if as.GetConcurrencyValue()*2 > portRange {
+				return nil, newError("not enough ports. concurrency = ", as.GetConcurrencyValue(), " ports: ", receiverSettings.PortRange.From, " - ", receiverSettings.PortRange.To)
+			}
+		}
+		receiverSettings.AllocationStrategy = as
+	}
 	if c.StreamSetting != nil {
 		ss, err := c.StreamSetting.Build()
 		if err != nil {
@@ -207,11 +290,44 @@ func (c *InboundDetourConfig) Build() (*core.InboundHandlerConfig, error) {
 		return nil, err
 	}
 	return &core.InboundHandlerConfig{
+		Tag:              c.Tag,
 		ReceiverSettings: serial.ToTypedMessage(receiverSettings),
 		ProxySettings:    serial.ToTypedMessage(ts),
 	}, nil
 }
 
+func (c *MuxBypassConfig) Build() (*proxyman.MultiplexingBypass, error) {
+	bypass := new(proxyman.MultiplexingBypass)
+	if c.Network != nil {
+		bypass.Network = c.Network.Build()
+	}
+	if c.Port != nil {
+		bypass.Port = c.Port.Build()
+	}
+	if len(bypass.Network) == 0 && bypass.Port == nil {
+		return nil, newError("mux bypass rule has neither network nor port")
+	}
+	return bypass, nil
+}
+
+func (m *MuxConfig) Build() (*proxyman.MultiplexingConfig, error) {
+	config := &proxyman.MultiplexingConfig{
+		Enabled:            m.Enabled,
+		Concurrency:        m.Concurrency,
+		MaxIdleConnections: m.MaxIdleConnections,
+		IdleTimeout:        m.IdleTimeout,
+		MaxLifetime:        m.MaxLifetime,
+	}
+	for _, b := range m.Bypass {
+		bypass, err := b.Build()
+		if err != nil {
+			return nil, err
+		}
+		config.Bypass = append(config.Bypass, bypass)
+	}
+	return config, nil
+}
+
 func (c *OutboundDetourConfig) Build() (*core.OutboundHandlerConfig, error) {
 	senderSettings := &proxyman.SenderConfig{}
 	if c.StreamSetting != nil {
@@ -221,6 +337,13 @@ func (c *OutboundDetourConfig) Build() (*core.OutboundHandlerConfig, error) {
 		}
 		senderSettings.StreamSettings = ss
 	}
+	if c.MuxSettings != nil {
+		ms, err := c.MuxSettings.Build()
+		if err != nil {
+			return nil, newError("failed to build mux config").Base(err)
+		}
+		senderSettings.MultiplexSettings = ms
+	}
 	settings := []byte("{}")
 	if c.Settings != nil {
 		settings = ([]byte)(*c.Settings)
@@ -234,6 +357,7 @@ func (c *OutboundDetourConfig) Build() (*core.OutboundHandlerConfig, error) {
 		return nil, err
 	}
 	return &core.OutboundHandlerConfig{
+		Tag:            c.Tag,
 		SenderSettings: serial.ToTypedMessage(senderSettings),
 		ProxySettings:  serial.ToTypedMessage(ts),
 	}, nil
@@ -246,6 +370,12 @@ func (c *Config) Override(o *Config, fn string) {
 	if o.Transport != nil {
 		c.Transport = o.Transport
 	}
+	if o.RouterConfig != nil {
+		c.RouterConfig = o.RouterConfig
+	}
+	if o.API != nil {
+		c.API = o.API
+	}
 	if o.InboundConfig != nil {
 		c.InboundConfig = o.InboundConfig
 	}
@@ -13,9 +13,15 @@ import (
 	_ "github.com/vmessocket/vmessocket/proxy/dns"
 	_ "github.com/vmessocket/vmessocket/proxy/freedom"
 	_ "github.com/vmessocket/vmessocket/proxy/http"
+	_ "github.com/vmessocket/vmessocket/proxy/loopback"
 	_ "github.com/vmessocket/vmessocket/proxy/vmess/inbound"
 	_ "github.com/vmessocket/vmessocket/proxy/vmess/outbound"
+	_ "github.com/vmessocket/vmessocket/transport/internet/grpc"
+	_ "github.com/vmessocket/vmessocket/transport/internet/headers/http"
+	_ "github.com/vmessocket/vmessocket/transport/internet/headers/noop"
+	_ "github.com/vmessocket/vmessocket/transport/internet/http"
 	_ "github.com/vmessocket/vmessocket/transport/internet/tcp"
+	_ "github.com/vmessocket/vmessocket/transport/internet/tls/command"
 	_ "github.com/vmessocket/vmessocket/transport/internet/udp"
 	_ "github.com/vmessocket/vmessocket/transport/internet/websocket"
 )
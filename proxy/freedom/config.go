@@ -0,0 +1,94 @@
+package freedom
+
+import (
+	"strings"
+
+	"github.com/vmessocket/vmessocket/common/net"
+	"github.com/vmessocket/vmessocket/common/strmatcher"
+)
+
+type rewriteRule struct {
+	domains []strmatcher.Matcher
+	ports   net.MemoryPortList
+	address net.Address
+	port    net.Port
+}
+
+type subdomainMatcher string
+
+func newRewriteRule(config *DestinationRewrite) (*rewriteRule, error) {
+	rule := new(rewriteRule)
+	for _, pattern := range config.Domain {
+		matcher, err := parseDomainPattern(pattern)
+		if err != nil {
+			return nil, newError("invalid domain pattern: ", pattern).Base(err)
+		}
+		rule.domains = append(rule.domains, matcher)
+	}
+	if config.Port != nil {
+		rule.ports = net.PortListFromProto(config.Port)
+	}
+	if config.Server == nil {
+		return nil, newError("rewrite rule has no target")
+	}
+	if isValidAddress(config.Server.Address) {
+		rule.address = config.Server.Address.AsAddress()
+	}
+	rule.port = net.Port(config.Server.Port)
+	if rule.address == nil && rule.port == 0 {
+		return nil, newError("rewrite rule has neither address nor port")
+	}
+	return rule, nil
+}
+
+func parseDomainPattern(pattern string) (strmatcher.Matcher, error) {
+	switch {
+	case strings.HasPrefix(pattern, "regexp:"):
+		return strmatcher.Regex.New(pattern[7:])
+	case strings.HasPrefix(pattern, "full:"):
+		return strmatcher.Full.New(strings.ToLower(pattern[5:]))
+	case strings.HasPrefix(pattern, "domain:"):
+		return strmatcher.Domain.New(strings.ToLower(pattern[7:]))
+	case strings.HasPrefix(pattern, "*."):
+		return subdomainMatcher(strings.ToLower(pattern[1:])), nil
+	default:
+		return strmatcher.Full.New(strings.ToLower(pattern))
+	}
+}
+
+func (r *rewriteRule) Apply(dest net.Destination) (net.Destination, bool) {
+	if len(r.ports) > 0 && !r.ports.Contains(dest.Port) {
+		return dest, false
+	}
+	if len(r.domains) > 0 {
+		if !dest.Address.Family().IsDomain() {
+			return dest, false
+		}
+		domain := strings.ToLower(dest.Address.Domain())
+		matched := false
+		for _, matcher := range r.domains {
+			if matcher.Match(domain) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return dest, false
+		}
+	}
+	if r.address != nil {
+		dest.Address = r.address
+	}
+	if r.port != 0 {
+		dest.Port = r.port
+	}
+	return dest, true
+}
+
+func (m subdomainMatcher) Match(s string) bool {
+	return len(s) > len(m) && strings.HasSuffix(s, string(m))
+}
+
+func (m subdomainMatcher) String() string {
+	return "*" + string(m)
+}
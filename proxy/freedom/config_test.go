@@ -0,0 +1,50 @@
+package freedom
+
+import (
+	"testing"
+
+	"github.com/vmessocket/vmessocket/common/net"
+	"github.com/vmessocket/vmessocket/common/protocol"
+)
+
+func TestRewriteRuleApply(t *testing.T) {
+	rule, err := newRewriteRule(&DestinationRewrite{
+		Domain: []string{"*.internal", "full:exact.example.com", "domain:example.org", "regexp:^api[0-9]+\\.example\\.net$"},
+		Server: &protocol.ServerEndpoint{
+			Address: net.NewIPOrDomain(net.ParseAddress("10.0.0.1")),
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCases := []struct {
+		domain  string
+		matched bool
+	}{
+		{domain: "service.internal", matched: true},
+		{domain: "a.b.INTERNAL", matched: true},
+		{domain: "internal"},
+		{domain: "notinternal"},
+		{domain: "exact.example.com", matched: true},
+		{domain: "sub.exact.example.com"},
+		{domain: "example.org", matched: true},
+		{domain: "www.example.org", matched: true},
+		{domain: "api1.example.net", matched: true},
+		{domain: "api.example.net"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.domain, func(t *testing.T) {
+			dest := net.TCPDestination(net.DomainAddress(tc.domain), 443)
+			rewritten, matched := rule.Apply(dest)
+			if matched != tc.matched {
+				t.Fatalf("expected matched %v, got %v", tc.matched, matched)
+			}
+			if matched && rewritten != net.TCPDestination(net.ParseAddress("10.0.0.1"), 443) {
+				t.Fatal("unexpected destination ", rewritten)
+			}
+		})
+	}
+	if _, matched := rule.Apply(net.TCPDestination(net.ParseAddress("192.0.2.1"), 443)); matched {
+		t.Fatal("domain rule matched an IP destination")
+	}
+}
@@ -0,0 +1,57 @@
+package freedom
+
+import (
+	"io"
+	"time"
+)
+
+type fragmentWriter struct {
+	writer  io.Writer
+	lengths []uint32
+	delay   time.Duration
+	done    bool
+}
+
+func newFragmentWriter(writer io.Writer, config *FragmentConfig) *fragmentWriter {
+	return &fragmentWriter{
+		writer:  writer,
+		lengths: config.Length,
+		delay:   time.Duration(config.Delay) * time.Millisecond,
+	}
+}
+
+func (w *fragmentWriter) Write(b []byte) (int, error) {
+	if w.done {
+		return w.writer.Write(b)
+	}
+	w.done = true
+	written := 0
+	for _, length := range w.lengths {
+		if written >= len(b) {
+			break
+		}
+		if length == 0 {
+			continue
+		}
+		end := written + int(length)
+		if end > len(b) {
+			end = len(b)
+		}
+		n, err := w.writer.Write(b[written:end])
+		written += n
+		if err != nil {
+			return written, err
+		}
+		if written < len(b) && w.delay > 0 {
+			time.Sleep(w.delay)
+		}
+	}
+	if written < len(b) {
+		n, err := w.writer.Write(b[written:])
+		written += n
+		if err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
@@ -2,23 +2,119 @@ package freedom
 
 import (
 	"context"
+	"time"
 
 	"github.com/vmessocket/vmessocket/common"
 	"github.com/vmessocket/vmessocket/common/buf"
-	"github.com/vmessocket/vmessocket/common/dice"
+	"github.com/vmessocket/vmessocket/common/errors"
 	"github.com/vmessocket/vmessocket/common/net"
+	"github.com/vmessocket/vmessocket/common/protocol/proxyproto"
 	"github.com/vmessocket/vmessocket/common/retry"
 	"github.com/vmessocket/vmessocket/common/session"
 	"github.com/vmessocket/vmessocket/common/signal"
 	"github.com/vmessocket/vmessocket/common/task"
+	"github.com/vmessocket/vmessocket/core"
 	"github.com/vmessocket/vmessocket/features/dns"
 	"github.com/vmessocket/vmessocket/transport"
 	"github.com/vmessocket/vmessocket/transport/internet"
 )
 
+const defaultFallbackDelay = 300 * time.Millisecond
+
+type dialResult struct {
+	conn  internet.Connection
+	err   error
+	index int
+}
+
 type Handler struct {
-	dns    dns.Client
-	config *Config
+	dns      dns.Client
+	config   *Config
+	rewrites []*rewriteRule
+}
+
+func closeDialResults(results <-chan dialResult, remaining int) {
+	for i := 0; i < remaining; i++ {
+		if r := <-results; r.conn != nil {
+			r.conn.Close()
+		}
+	}
+}
+
+func dialHappyEyeballs(ctx context.Context, dialer internet.Dialer, dest net.Destination, addrs []net.Address, delay time.Duration) (internet.Connection, context.CancelFunc, error) {
+	results := make(chan dialResult, len(addrs))
+	cancels := make([]context.CancelFunc, 0, len(addrs))
+	winner := -1
+	defer func() {
+		for idx, cancel := range cancels {
+			if idx != winner {
+				cancel()
+			}
+		}
+	}()
+	pending := 0
+	startNext := func() <-chan time.Time {
+		index := len(cancels)
+		attemptCtx, cancel := context.WithCancel(ctx)
+		cancels = append(cancels, cancel)
+		attemptDest := dest
+		attemptDest.Address = addrs[index]
+		pending++
+		go func() {
+			conn, err := dialer.Dial(attemptCtx, attemptDest)
+			results <- dialResult{conn: conn, err: err, index: index}
+		}()
+		if len(cancels) < len(addrs) {
+			return time.After(delay)
+		}
+		return nil
+	}
+	fallback := startNext()
+	var errs []error
+	for pending > 0 {
+		select {
+		case r := <-results:
+			pending--
+			if r.err == nil {
+				winner = r.index
+				go closeDialResults(results, pending)
+				return r.conn, cancels[r.index], nil
+			}
+			errs = append(errs, r.err)
+			if len(cancels) < len(addrs) {
+				fallback = startNext()
+			}
+		case <-fallback:
+			fallback = startNext()
+		case <-ctx.Done():
+			go closeDialResults(results, pending)
+			return nil, nil, ctx.Err()
+		}
+	}
+	return nil, nil, errors.Combine(errs...)
+}
+
+func filterIPs(ips []net.IP, ipv4 bool) []net.IP {
+	filtered := make([]net.IP, 0, len(ips))
+	for _, ip := range ips {
+		if (ip.To4() != nil) == ipv4 {
+			filtered = append(filtered, ip)
+		}
+	}
+	return filtered
+}
+
+func interleaveIPs(preferred []net.IP, others []net.IP) []net.Address {
+	addrs := make([]net.Address, 0, len(preferred)+len(others))
+	for i := 0; i < len(preferred) || i < len(others); i++ {
+		if i < len(preferred) {
+			addrs = append(addrs, net.IPAddress(preferred[i]))
+		}
+		if i < len(others) {
+			addrs = append(addrs, net.IPAddress(others[i]))
+		}
+	}
+	return addrs
 }
 
 func isValidAddress(addr *net.IPOrDomain) bool {
@@ -29,9 +125,36 @@ func isValidAddress(addr *net.IPOrDomain) bool {
 	return a != net.AnyIP
 }
 
+func (h *Handler) dial(ctx context.Context, dialer internet.Dialer, dest net.Destination, addrs []net.Address) (internet.Connection, context.CancelFunc, error) {
+	if len(addrs) == 0 {
+		conn, err := dialer.Dial(ctx, dest)
+		return conn, nil, err
+	}
+	if dest.Network != net.Network_TCP {
+		dest.Address = addrs[0]
+		conn, err := dialer.Dial(ctx, dest)
+		return conn, nil, err
+	}
+	return dialHappyEyeballs(ctx, dialer, dest, addrs, h.fallbackDelay())
+}
+
+func (h *Handler) fallbackDelay() time.Duration {
+	if h.config.FallbackDelay > 0 {
+		return time.Duration(h.config.FallbackDelay) * time.Millisecond
+	}
+	return defaultFallbackDelay
+}
+
 func (h *Handler) Init(config *Config, d dns.Client) error {
 	h.config = config
 	h.dns = d
+	for _, r := range config.Rewrite {
+		rule, err := newRewriteRule(r)
+		if err != nil {
+			return newError("failed to build rewrite rule").Base(err)
+		}
+		h.rewrites = append(h.rewrites, rule)
+	}
 	return nil
 }
 
@@ -40,8 +163,10 @@ func (h *Handler) Process(ctx context.Context, link *transport.Link, dialer inte
 	if outbound == nil || !outbound.Target.IsValid() {
 		return newError("target not specified.")
 	}
-	destination := outbound.Target
-	if h.config.DestinationOverride != nil {
+	destination, rewritten := h.rewrite(outbound.Target)
+	if rewritten {
+		newError("rewrote destination ", outbound.Target, " to ", destination).AtDebug().WriteToLog(session.ExportIDToError(ctx))
+	} else if h.config.DestinationOverride != nil {
 		server := h.config.DestinationOverride.Server
 		if isValidAddress(server.Address) {
 			destination.Address = server.Address.AsAddress()
@@ -50,29 +175,58 @@ func (h *Handler) Process(ctx context.Context, link *transport.Link, dialer inte
 			destination.Port = net.Port(server.Port)
 		}
 	}
+	var addrs []net.Address
+	if h.config.DomainStrategy != Config_AS_IS && destination.Address.Family().IsDomain() {
+		addrs = h.resolveIP(ctx, destination.Address.Domain(), destination.Network)
+		if len(addrs) == 0 {
+			return newError("failed to resolve ", destination.Address, " with domain strategy ", h.config.DomainStrategy)
+		}
+		newError("resolved ", destination.Address, " to ", addrs).AtDebug().WriteToLog(session.ExportIDToError(ctx))
+	}
 	newError("opening connection to ", destination).WriteToLog(session.ExportIDToError(ctx))
 	input := link.Reader
 	output := link.Writer
 	var conn internet.Connection
+	var cancelDial context.CancelFunc
 	err := retry.ExponentialBackoff(5, 100).On(func() error {
-		dialDest := destination
-		rawConn, err := dialer.Dial(ctx, dialDest)
+		rawConn, cancel, err := h.dial(ctx, dialer, destination, addrs)
 		if err != nil {
 			return err
 		}
 		conn = rawConn
+		cancelDial = cancel
 		return nil
 	})
 	if err != nil {
 		return newError("failed to open connection to ", destination).Base(err)
 	}
+	if cancelDial != nil {
+		defer cancelDial()
+	}
 	defer conn.Close()
+	if h.config.SendProxyProtocol > 0 && destination.Network == net.Network_TCP {
+		if err := proxyproto.WriteHeader(ctx, conn, h.config.SendProxyProtocol, conn.RemoteAddr()); err != nil {
+			return newError("failed to send PROXY protocol header to ", destination).Base(err)
+		}
+	}
 	ctx, cancel := context.WithCancel(ctx)
 	timer := signal.CancelAfterInactivity(ctx, cancel)
 	requestDone := func() error {
 		var writer buf.Writer
 		if destination.Network == net.Network_TCP {
-			writer = buf.NewWriter(conn)
+			if h.config.Fragment != nil && len(h.config.Fragment.Length) > 0 {
+				writer = buf.NewWriter(newFragmentWriter(conn, h.config.Fragment))
+			} else {
+				writer = buf.NewWriter(conn)
+			}
+		} else if pc, ok := conn.(packetConn); ok {
+			var lookup func(string) []net.Address
+			if h.config.DomainStrategy != Config_AS_IS {
+				lookup = func(domain string) []net.Address {
+					return h.resolveIP(ctx, domain, net.Network_UDP)
+				}
+			}
+			writer = newPacketWriter(pc, lookup)
 		} else {
 			writer = &buf.SequentialWriter{Writer: conn}
 		}
@@ -85,6 +239,8 @@ func (h *Handler) Process(ctx context.Context, link *transport.Link, dialer inte
 		var reader buf.Reader
 		if destination.Network == net.Network_TCP {
 			reader = buf.NewReader(conn)
+		} else if pc, ok := conn.(packetConn); ok {
+			reader = &packetReader{conn: pc}
 		} else {
 			reader = buf.NewPacketReader(conn)
 		}
@@ -99,21 +255,67 @@ func (h *Handler) Process(ctx context.Context, link *transport.Link, dialer inte
 	return nil
 }
 
-func (h *Handler) resolveIP(ctx context.Context, domain string, localAddr net.Address) net.Address {
-	lookupFunc := h.dns.LookupIP
-	ips, err := lookupFunc(domain)
-	if err != nil {
-		newError("failed to get IP address for domain ", domain).Base(err).WriteToLog(session.ExportIDToError(ctx))
+func (h *Handler) lookupIPv4(ctx context.Context, domain string) []net.IP {
+	var ips []net.IP
+	var err error
+	if lookup, ok := h.dns.(dns.IPv4Lookup); ok {
+		ips, err = lookup.LookupIPv4(domain)
+	} else {
+		ips, err = h.dns.LookupIP(domain)
+		ips = filterIPs(ips, true)
 	}
-	if len(ips) == 0 {
-		return nil
+	if err != nil && err != dns.ErrEmptyResponse {
+		newError("failed to get IPv4 address for domain ", domain).Base(err).WriteToLog(session.ExportIDToError(ctx))
 	}
-	return net.IPAddress(ips[dice.Roll(len(ips))])
+	return ips
+}
+
+func (h *Handler) lookupIPv6(ctx context.Context, domain string) []net.IP {
+	var ips []net.IP
+	var err error
+	if lookup, ok := h.dns.(dns.IPv6Lookup); ok {
+		ips, err = lookup.LookupIPv6(domain)
+	} else {
+		ips, err = h.dns.LookupIP(domain)
+		ips = filterIPs(ips, false)
+	}
+	if err != nil && err != dns.ErrEmptyResponse {
+		newError("failed to get IPv6 address for domain ", domain).Base(err).WriteToLog(session.ExportIDToError(ctx))
+	}
+	return ips
+}
+
+func (h *Handler) resolveIP(ctx context.Context, domain string, network net.Network) []net.Address {
+	switch h.config.DomainStrategy {
+	case Config_USE_IP4:
+		return interleaveIPs(h.lookupIPv4(ctx, domain), nil)
+	case Config_USE_IP6:
+		return interleaveIPs(h.lookupIPv6(ctx, domain), nil)
+	default:
+		if network != net.Network_TCP {
+			return interleaveIPs(h.lookupIPv4(ctx, domain), h.lookupIPv6(ctx, domain))
+		}
+		return interleaveIPs(h.lookupIPv6(ctx, domain), h.lookupIPv4(ctx, domain))
+	}
+}
+
+func (h *Handler) rewrite(dest net.Destination) (net.Destination, bool) {
+	for _, rule := range h.rewrites {
+		if newDest, ok := rule.Apply(dest); ok {
+			return newDest, true
+		}
+	}
+	return dest, false
 }
 
 func init() {
 	common.Must(common.RegisterConfig((*Config)(nil), func(ctx context.Context, config interface{}) (interface{}, error) {
 		h := new(Handler)
+		if err := core.RequireFeatures(ctx, func(d dns.Client) error {
+			return h.Init(config.(*Config), d)
+		}); err != nil {
+			return nil, err
+		}
 		return h, nil
 	}))
 }
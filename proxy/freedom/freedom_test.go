@@ -0,0 +1,162 @@
+package freedom
+
+import (
+	"context"
+	gonet "net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/vmessocket/vmessocket/common/net"
+	"github.com/vmessocket/vmessocket/transport/internet"
+)
+
+type testDialer struct {
+	sync.Mutex
+	delays   map[string]time.Duration
+	failures map[string]bool
+	contexts map[string]context.Context
+}
+
+func (d *testDialer) Address() net.Address {
+	return nil
+}
+
+func (d *testDialer) Dial(ctx context.Context, dest net.Destination) (internet.Connection, error) {
+	addr := dest.Address.IP().String()
+	d.Lock()
+	d.contexts[addr] = ctx
+	d.Unlock()
+	select {
+	case <-time.After(d.delays[addr]):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if d.failures[addr] {
+		return nil, newError("failed to dial ", addr)
+	}
+	client, server := gonet.Pipe()
+	server.Close()
+	return client, nil
+}
+
+func (d *testDialer) context(addr string) context.Context {
+	d.Lock()
+	defer d.Unlock()
+	return d.contexts[addr]
+}
+
+func TestDialHappyEyeballs(t *testing.T) {
+	testCases := []struct {
+		name     string
+		delays   map[string]time.Duration
+		failures map[string]bool
+		winner   string
+	}{
+		{
+			name:   "first succeeds",
+			winner: "2001:db8::1",
+		},
+		{
+			name:   "fallback wins",
+			delays: map[string]time.Duration{"2001:db8::1": time.Second},
+			winner: "192.0.2.1",
+		},
+		{
+			name:     "first fails",
+			failures: map[string]bool{"2001:db8::1": true},
+			winner:   "192.0.2.1",
+		},
+		{
+			name:     "all fail",
+			failures: map[string]bool{"2001:db8::1": true, "192.0.2.1": true},
+		},
+	}
+	addrs := []net.Address{net.ParseAddress("2001:db8::1"), net.ParseAddress("192.0.2.1")}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dialer := &testDialer{
+				delays:   tc.delays,
+				failures: tc.failures,
+				contexts: make(map[string]context.Context),
+			}
+			dest := net.TCPDestination(net.DomainAddress("example.com"), 443)
+			conn, cancel, err := dialHappyEyeballs(context.Background(), dialer, dest, addrs, 10*time.Millisecond)
+			if tc.winner == "" {
+				if err == nil || conn != nil || cancel != nil {
+					t.Fatal("expected failure, got ", conn, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer conn.Close()
+			ctx := dialer.context(tc.winner)
+			if ctx.Err() != nil {
+				t.Fatal("winning attempt cancelled before the connection closed")
+			}
+			cancel()
+			if ctx.Err() == nil {
+				t.Fatal("winning attempt not cancelled by the returned cancel func")
+			}
+		})
+	}
+}
+
+type testDNS []net.IP
+
+func (testDNS) Close() error {
+	return nil
+}
+
+func (d testDNS) LookupIP(domain string) ([]net.IP, error) {
+	return d, nil
+}
+
+func (testDNS) Start() error {
+	return nil
+}
+
+func (testDNS) Type() interface{} {
+	return nil
+}
+
+func TestDialUDPPrefersDomainStrategyFamily(t *testing.T) {
+	testCases := []struct {
+		strategy Config_DomainStrategy
+		network  net.Network
+		dialed   string
+	}{
+		{strategy: Config_USE_IP, network: net.Network_UDP, dialed: "192.0.2.1"},
+		{strategy: Config_USE_IP4, network: net.Network_UDP, dialed: "192.0.2.1"},
+		{strategy: Config_USE_IP6, network: net.Network_UDP, dialed: "2001:db8::1"},
+		{strategy: Config_USE_IP, network: net.Network_TCP, dialed: "2001:db8::1"},
+	}
+	for _, tc := range testCases {
+		h := &Handler{
+			config: &Config{DomainStrategy: tc.strategy},
+			dns:    testDNS{net.ParseIP("2001:db8::1"), net.ParseIP("192.0.2.1")},
+		}
+		dialer := &testDialer{
+			delays:   map[string]time.Duration{"192.0.2.1": 20 * time.Millisecond},
+			contexts: make(map[string]context.Context),
+		}
+		dest := net.Destination{Network: tc.network, Address: net.DomainAddress("example.com"), Port: 53}
+		addrs := h.resolveIP(context.Background(), "example.com", tc.network)
+		conn, cancel, err := h.dial(context.Background(), dialer, dest, addrs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		conn.Close()
+		if cancel != nil {
+			cancel()
+		}
+		if dialer.context(tc.dialed) == nil {
+			t.Error(tc.strategy, " over ", tc.network, ": ", tc.dialed, " was not dialed")
+		}
+		if tc.network == net.Network_UDP && len(dialer.contexts) != 1 {
+			t.Error(tc.strategy, " over UDP dialed ", len(dialer.contexts), " addresses")
+		}
+	}
+}
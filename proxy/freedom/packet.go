@@ -0,0 +1,107 @@
+package freedom
+
+import (
+	"sync"
+
+	"github.com/vmessocket/vmessocket/common/buf"
+	"github.com/vmessocket/vmessocket/common/net"
+)
+
+const maxResolvedAddresses = 256
+
+type packetConn interface {
+	net.Conn
+	ReadFrom(p []byte) (int, net.Addr, error)
+	WriteTo(p []byte, addr net.Addr) (int, error)
+}
+
+type packetReader struct {
+	conn packetConn
+}
+
+type packetWriter struct {
+	sync.Mutex
+	conn     packetConn
+	lookup   func(domain string) []net.Address
+	resolved map[net.Destination]net.Addr
+}
+
+func newPacketWriter(conn packetConn, lookup func(domain string) []net.Address) *packetWriter {
+	return &packetWriter{
+		conn:     conn,
+		lookup:   lookup,
+		resolved: make(map[net.Destination]net.Addr),
+	}
+}
+
+func (r *packetReader) ReadMultiBuffer() (buf.MultiBuffer, error) {
+	b := buf.New()
+	n, addr, err := r.conn.ReadFrom(b.Extend(buf.Size))
+	if err != nil {
+		b.Release()
+		return nil, err
+	}
+	b.Resize(0, int32(n))
+	if udpAddr, ok := addr.(*net.UDPAddr); ok {
+		net.SetPacketDestination(b, net.UDPDestination(net.IPAddress(udpAddr.IP), net.Port(udpAddr.Port)))
+	}
+	return buf.MultiBuffer{b}, nil
+}
+
+func (w *packetWriter) resolve(dest net.Destination) (net.Addr, error) {
+	if dest.Address.Family().IsIP() {
+		return &net.UDPAddr{
+			IP:   dest.Address.IP(),
+			Port: int(dest.Port),
+		}, nil
+	}
+	w.Lock()
+	defer w.Unlock()
+	if addr, found := w.resolved[dest]; found {
+		return addr, nil
+	}
+	var addr net.Addr
+	if w.lookup != nil {
+		ips := w.lookup(dest.Address.Domain())
+		if len(ips) == 0 {
+			return nil, newError("no IP address for ", dest.Address)
+		}
+		addr = &net.UDPAddr{
+			IP:   ips[0].IP(),
+			Port: int(dest.Port),
+		}
+	} else {
+		udpAddr, err := net.ResolveUDPAddr("udp", dest.NetAddr())
+		if err != nil {
+			return nil, err
+		}
+		addr = udpAddr
+	}
+	if len(w.resolved) >= maxResolvedAddresses {
+		w.resolved = make(map[net.Destination]net.Addr)
+	}
+	w.resolved[dest] = addr
+	return addr, nil
+}
+
+func (w *packetWriter) WriteMultiBuffer(mb buf.MultiBuffer) error {
+	defer buf.ReleaseMulti(mb)
+	for _, b := range mb {
+		dest, ok := net.PacketDestination(b)
+		if !ok {
+			if _, err := w.conn.Write(b.Bytes()); err != nil {
+				return err
+			}
+			continue
+		}
+		addr, err := w.resolve(dest)
+		if err != nil {
+			newError("failed to resolve ", dest).Base(err).AtDebug().WriteToLog()
+			continue
+		}
+		if _, err := w.conn.WriteTo(b.Bytes(), addr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
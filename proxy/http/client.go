@@ -3,11 +3,13 @@ package http
 import (
 	"bufio"
 	"context"
+	"crypto/tls"
 	"encoding/base64"
 	"io"
 	"net/http"
 	"net/url"
 	"sync"
+	"time"
 
 	"golang.org/x/net/http2"
 
@@ -24,6 +26,8 @@ import (
 	"github.com/vmessocket/vmessocket/transport/internet"
 )
 
+const h2ReadIdleTimeout = 30 * time.Second
+
 var (
 	cachedH2Mutex sync.Mutex
 	cachedH2Conns map[net.Destination]h2Conn
@@ -31,6 +35,8 @@ var (
 
 type Client struct {
 	serverPicker protocol.ServerPicker
+	tlsConfig    *tls.Config
+	tlsConfigs   map[net.Destination]*tls.Config
 }
 
 type h2Conn struct {
@@ -44,28 +50,59 @@ type http2Conn struct {
 	out io.ReadCloser
 }
 
+func cachedH2Conn(dest net.Destination) (h2Conn, bool) {
+	cachedH2Mutex.Lock()
+	defer cachedH2Mutex.Unlock()
+	conn, found := cachedH2Conns[dest]
+	if !found {
+		return h2Conn{}, false
+	}
+	if conn.h2Conn.CanTakeNewRequest() {
+		return conn, true
+	}
+	delete(cachedH2Conns, dest)
+	go conn.h2Conn.Shutdown(context.Background())
+	return h2Conn{}, false
+}
+
 func NewClient(ctx context.Context, config *ClientConfig) (*Client, error) {
 	serverList := protocol.NewServerList()
+	client := &Client{
+		tlsConfigs: make(map[net.Destination]*tls.Config),
+	}
 	for _, rec := range config.Server {
 		s, err := protocol.NewServerSpecFromPB(rec)
 		if err != nil {
 			return nil, newError("failed to get server spec").Base(err)
 		}
 		serverList.AddServer(s)
+		if settings, found := config.ServerTlsSettings[s.Destination().NetAddr()]; found {
+			tlsConfig, err := settings.GetTLSConfig()
+			if err != nil {
+				return nil, newError("failed to build TLS config for ", s.Destination()).Base(err)
+			}
+			client.tlsConfigs[s.Destination()] = tlsConfig
+		}
 	}
 	if serverList.Size() == 0 {
 		return nil, newError("0 target server")
 	}
-	return &Client{
-		serverPicker: protocol.NewRoundRobinServerPicker(serverList),
-	}, nil
+	client.serverPicker = protocol.NewRoundRobinServerPicker(serverList)
+	if config.TlsSettings != nil {
+		tlsConfig, err := config.TlsSettings.GetTLSConfig()
+		if err != nil {
+			return nil, newError("failed to build TLS config").Base(err)
+		}
+		client.tlsConfig = tlsConfig
+	}
+	return client, nil
 }
 
 func newHTTP2Conn(c net.Conn, pipedReqBody *io.PipeWriter, respBody io.ReadCloser) net.Conn {
 	return &http2Conn{Conn: c, in: pipedReqBody, out: respBody}
 }
 
-func setUpHTTPTunnel(ctx context.Context, dest net.Destination, target string, user *protocol.MemoryUser, dialer internet.Dialer, firstPayload []byte) (net.Conn, error) {
+func setUpHTTPTunnel(ctx context.Context, dest net.Destination, target string, user *protocol.MemoryUser, dialer internet.Dialer, tlsConfig *tls.Config, firstPayload []byte) (net.Conn, error) {
 	req := &http.Request{
 		Method: http.MethodConnect,
 		URL:    &url.URL{Host: target},
@@ -74,8 +111,13 @@ func setUpHTTPTunnel(ctx context.Context, dest net.Destination, target string, u
 	}
 	if user != nil && user.Account != nil {
 		account := user.Account.(*Account)
-		auth := account.GetUsername() + ":" + account.GetPassword()
-		req.Header.Set("Proxy-Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(auth)))
+		if len(account.GetUsername()) > 0 || len(account.GetPassword()) > 0 {
+			auth := account.GetUsername() + ":" + account.GetPassword()
+			req.Header.Set("Proxy-Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(auth)))
+		}
+		for key, value := range account.GetHeader() {
+			req.Header.Set(key, value)
+		}
 	}
 	connectHTTP1 := func(rawConn net.Conn) (net.Conn, error) {
 		req.Header.Set("Proxy-Connection", "Keep-Alive")
@@ -108,53 +150,50 @@ func setUpHTTPTunnel(ctx context.Context, dest net.Destination, target string, u
 		}()
 		resp, err := h2clientConn.RoundTrip(req) // nolint: bodyclose
 		if err != nil {
-			rawConn.Close()
+			pw.CloseWithError(err)
 			return nil, err
 		}
 		wg.Wait()
 		if pErr != nil {
-			rawConn.Close()
+			resp.Body.Close()
 			return nil, pErr
 		}
 		if resp.StatusCode != http.StatusOK {
-			rawConn.Close()
+			pw.Close()
+			resp.Body.Close()
 			return nil, newError("Proxy responded with non 200 code: " + resp.Status)
 		}
 		return newHTTP2Conn(rawConn, pw, resp.Body), nil
 	}
-	cachedH2Mutex.Lock()
-	cachedConn, cachedConnFound := cachedH2Conns[dest]
-	cachedH2Mutex.Unlock()
-	if cachedConnFound {
-		rc, cc := cachedConn.rawConn, cachedConn.h2Conn
-		if cc.CanTakeNewRequest() {
-			proxyConn, err := connectHTTP2(rc, cc)
-			if err != nil {
-				return nil, err
-			}
-			return proxyConn, nil
-		}
+	if cachedConn, found := cachedH2Conn(dest); found {
+		return connectHTTP2(cachedConn.rawConn, cachedConn.h2Conn)
 	}
 	rawConn, err := dialer.Dial(ctx, dest)
 	if err != nil {
 		return nil, err
 	}
-	iConn := rawConn
-	if statConn, ok := iConn.(*internet.StatCouterConnection); ok {
-		iConn = statConn.Connection
-	}
 	nextProto := ""
+	if tlsConfig != nil {
+		config := tlsConfig.Clone()
+		if len(config.ServerName) == 0 {
+			config.ServerName = dest.Address.String()
+		}
+		tlsConn := tls.Client(rawConn, config)
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			rawConn.Close()
+			return nil, newError("failed to establish TLS with proxy server ", dest).Base(err)
+		}
+		rawConn = tlsConn
+		nextProto = tlsConn.ConnectionState().NegotiatedProtocol
+	}
 	switch nextProto {
 	case "", "http/1.1":
 		return connectHTTP1(rawConn)
 	case "h2":
-		t := http2.Transport{}
-		h2clientConn, err := t.NewClientConn(rawConn)
-		if err != nil {
-			rawConn.Close()
-			return nil, err
+		t := http2.Transport{
+			ReadIdleTimeout: h2ReadIdleTimeout,
 		}
-		proxyConn, err := connectHTTP2(rawConn, h2clientConn)
+		h2clientConn, err := t.NewClientConn(rawConn)
 		if err != nil {
 			rawConn.Close()
 			return nil, err
@@ -163,12 +202,15 @@ func setUpHTTPTunnel(ctx context.Context, dest net.Destination, target string, u
 		if cachedH2Conns == nil {
 			cachedH2Conns = make(map[net.Destination]h2Conn)
 		}
+		if previous, found := cachedH2Conns[dest]; found {
+			go previous.h2Conn.Shutdown(context.Background())
+		}
 		cachedH2Conns[dest] = h2Conn{
 			rawConn: rawConn,
 			h2Conn:  h2clientConn,
 		}
 		cachedH2Mutex.Unlock()
-		return proxyConn, err
+		return connectHTTP2(rawConn, h2clientConn)
 	default:
 		return nil, newError("negotiated unsupported application layer protocol: " + nextProto)
 	}
@@ -203,7 +245,11 @@ func (c *Client) Process(ctx context.Context, link *transport.Link, dialer inter
 		server := c.serverPicker.PickServer()
 		dest := server.Destination()
 		user = server.PickUser()
-		netConn, err := setUpHTTPTunnel(ctx, dest, targetAddr, user, dialer, firstPayload)
+		tlsConfig, found := c.tlsConfigs[dest]
+		if !found {
+			tlsConfig = c.tlsConfig
+		}
+		netConn, err := setUpHTTPTunnel(ctx, dest, targetAddr, user, dialer, tlsConfig, firstPayload)
 		if netConn != nil {
 			if _, ok := netConn.(*http2Conn); !ok {
 				if _, err := netConn.Write(firstPayload); err != nil {
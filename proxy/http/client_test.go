@@ -0,0 +1,189 @@
+package http
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	gonet "net"
+	"net/http"
+	"testing"
+	"time"
+
+	"golang.org/x/net/http2"
+
+	"github.com/vmessocket/vmessocket/common"
+	"github.com/vmessocket/vmessocket/common/buf"
+	"github.com/vmessocket/vmessocket/common/net"
+	"github.com/vmessocket/vmessocket/common/protocol"
+	"github.com/vmessocket/vmessocket/common/protocol/tls/cert"
+	"github.com/vmessocket/vmessocket/common/session"
+	"github.com/vmessocket/vmessocket/transport"
+	"github.com/vmessocket/vmessocket/transport/internet"
+	"github.com/vmessocket/vmessocket/transport/pipe"
+)
+
+type tcpDialer struct{}
+
+func (tcpDialer) Address() net.Address {
+	return nil
+}
+
+func (tcpDialer) Dial(ctx context.Context, dest net.Destination) (internet.Connection, error) {
+	return gonet.Dial("tcp", dest.NetAddr())
+}
+
+func echoConnect(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodConnect {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	if r.Host == "denied.example:443" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.(http.Flusher).Flush()
+	b := make([]byte, 1024)
+	for {
+		n, err := r.Body.Read(b)
+		if n > 0 {
+			w.Write(b[:n])
+			w.(http.Flusher).Flush()
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+func startH2Proxy(t *testing.T, name string) (net.Destination, []byte) {
+	t.Helper()
+	certificate := cert.MustGenerate(nil, cert.Authority(true), cert.CommonName(name), cert.DNSNames(name))
+	certPEM, keyPEM := certificate.ToPEM()
+	keyPair, err := tls.X509KeyPair(certPEM, keyPEM)
+	common.Must(err)
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+		Certificates: []tls.Certificate{keyPair},
+		NextProtos:   []string{"h2"},
+	})
+	common.Must(err)
+	t.Cleanup(func() {
+		listener.Close()
+	})
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				if err := conn.(*tls.Conn).Handshake(); err != nil {
+					conn.Close()
+					return
+				}
+				new(http2.Server).ServeConn(conn, &http2.ServeConnOpts{
+					Handler: http.HandlerFunc(echoConnect),
+				})
+			}()
+		}
+	}()
+	return net.DestinationFromAddr(listener.Addr()), certPEM
+}
+
+func readEcho(t *testing.T, conn io.Reader, expected string) {
+	t.Helper()
+	b := make([]byte, len(expected))
+	if _, err := io.ReadFull(conn, b); err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != expected {
+		t.Fatal("unexpected echo: ", string(b))
+	}
+}
+
+func TestHTTP2RejectedConnectKeepsSharedConnection(t *testing.T) {
+	dest, ca := startH2Proxy(t, "proxy.example")
+	tlsConfig, err := (&TLSConfig{
+		ServerName:           "proxy.example",
+		CertificateAuthority: [][]byte{ca},
+	}).GetTLSConfig()
+	common.Must(err)
+	ctx := context.Background()
+	tunnel, err := setUpHTTPTunnel(ctx, dest, "allowed.example:443", nil, tcpDialer{}, tlsConfig, []byte("first"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tunnel.Close()
+	readEcho(t, tunnel, "first")
+	shared, found := cachedH2Conn(dest)
+	if !found {
+		t.Fatal("h2 connection is not cached")
+	}
+	if _, err := setUpHTTPTunnel(ctx, dest, "denied.example:443", nil, tcpDialer{}, tlsConfig, []byte("second")); err == nil {
+		t.Fatal("expected rejected CONNECT to fail")
+	}
+	if _, err := tunnel.Write([]byte("third")); err != nil {
+		t.Fatal(err)
+	}
+	readEcho(t, tunnel, "third")
+	if cached, found := cachedH2Conn(dest); !found || cached.h2Conn != shared.h2Conn {
+		t.Fatal("rejected CONNECT replaced the shared connection")
+	}
+	common.Must(shared.h2Conn.Close())
+	if _, found := cachedH2Conn(dest); found {
+		t.Fatal("closed connection is still cached")
+	}
+	cachedH2Mutex.Lock()
+	_, found = cachedH2Conns[dest]
+	cachedH2Mutex.Unlock()
+	if found {
+		t.Fatal("closed connection was not evicted")
+	}
+}
+
+func TestPerServerTLSSettings(t *testing.T) {
+	dest, ca := startH2Proxy(t, "first.example")
+	_, otherCA := startH2Proxy(t, "second.example")
+	client, err := NewClient(context.Background(), &ClientConfig{
+		Server: []*protocol.ServerEndpoint{
+			{
+				Address: net.NewIPOrDomain(dest.Address),
+				Port:    uint32(dest.Port),
+			},
+		},
+		TlsSettings: &TLSConfig{
+			ServerName:           "second.example",
+			CertificateAuthority: [][]byte{otherCA},
+		},
+		ServerTlsSettings: map[string]*TLSConfig{
+			dest.NetAddr(): {
+				ServerName:           "first.example",
+				CertificateAuthority: [][]byte{ca},
+			},
+		},
+	})
+	common.Must(err)
+	uplinkReader, uplinkWriter := pipe.New()
+	downlinkReader, downlinkWriter := pipe.New()
+	ctx, cancel := context.WithCancel(session.ContextWithOutbound(context.Background(), &session.Outbound{
+		Target: net.TCPDestination(net.DomainAddress("allowed.example"), 443),
+	}))
+	done := make(chan error, 1)
+	go func() {
+		done <- client.Process(ctx, &transport.Link{
+			Reader: uplinkReader,
+			Writer: downlinkWriter,
+		}, tcpDialer{})
+	}()
+	common.Must(uplinkWriter.WriteMultiBuffer(buf.MergeBytes(nil, []byte("payload"))))
+	mb, err := downlinkReader.ReadMultiBufferTimeout(5 * time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mb.String() != "payload" {
+		t.Error("unexpected response: ", mb.String())
+	}
+	buf.ReleaseMulti(mb)
+	cancel()
+	<-done
+}
@@ -1,6 +1,11 @@
 package http
 
-import "github.com/vmessocket/vmessocket/common/protocol"
+import (
+	"crypto/tls"
+	"crypto/x509"
+
+	"github.com/vmessocket/vmessocket/common/protocol"
+)
 
 func (a *Account) AsAccount() (protocol.Account, error) {
 	return a, nil
@@ -23,3 +28,24 @@ func (sc *ServerConfig) HasAccount(username, password string) bool {
 	}
 	return p == password
 }
+
+func (c *TLSConfig) GetTLSConfig() (*tls.Config, error) {
+	config := &tls.Config{
+		ServerName:         c.ServerName,
+		InsecureSkipVerify: c.AllowInsecure,
+		NextProtos:         c.NextProtocol,
+	}
+	if len(config.NextProtos) == 0 {
+		config.NextProtos = []string{"h2", "http/1.1"}
+	}
+	if len(c.CertificateAuthority) > 0 {
+		pool := x509.NewCertPool()
+		for _, ca := range c.CertificateAuthority {
+			if !pool.AppendCertsFromPEM(ca) {
+				return nil, newError("failed to parse certificate authority")
+			}
+		}
+		config.RootCAs = pool
+	}
+	return config, nil
+}
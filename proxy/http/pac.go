@@ -0,0 +1,98 @@
+package http
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/vmessocket/vmessocket/common/net"
+)
+
+const pacTemplate = `var direct = %DIRECT%;
+var proxy = %PROXY%;
+
+function match(host, list) {
+  for (var i = 0; i < list.length; i++) {
+    if (host === list[i] || dnsDomainIs(host, "." + list[i])) {
+      return true;
+    }
+  }
+  return false;
+}
+
+function FindProxyForURL(url, host) {
+  host = host.toLowerCase();
+  if (match(host, direct)) {
+    return "DIRECT";
+  }
+  if (match(host, proxy)) {
+    return %ACTION%;
+  }
+  return %DEFAULT%;
+}
+`
+
+func hostWithPort(host string, port net.Port, replacePort bool) string {
+	if port == 0 {
+		return host
+	}
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		if !replacePort {
+			return host
+		}
+		host = h
+	}
+	return net.JoinHostPort(strings.TrimSuffix(strings.TrimPrefix(host, "["), "]"), port.String())
+}
+
+func normalizeDomainList(domains []string) []string {
+	list := make([]string, 0, len(domains))
+	for _, domain := range domains {
+		domain = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(domain)), ".")
+		if len(domain) > 0 {
+			list = append(list, domain)
+		}
+	}
+	return list
+}
+
+func (c *PacConfig) Generate(host string, port net.Port) ([]byte, error) {
+	if len(c.ProxyAddress) > 0 {
+		host = hostWithPort(c.ProxyAddress, port, false)
+	} else {
+		host = hostWithPort(host, port, true)
+	}
+	direct, err := json.Marshal(normalizeDomainList(c.DirectDomain))
+	if err != nil {
+		return nil, newError("failed to encode direct domain list").Base(err)
+	}
+	proxy, err := json.Marshal(normalizeDomainList(c.ProxyDomain))
+	if err != nil {
+		return nil, newError("failed to encode proxy domain list").Base(err)
+	}
+	action, err := json.Marshal("PROXY " + host)
+	if err != nil {
+		return nil, newError("failed to encode proxy address").Base(err)
+	}
+	defaultAction := action
+	if c.DefaultDirect {
+		defaultAction = []byte(`"DIRECT"`)
+	}
+	script := strings.NewReplacer(
+		"%DIRECT%", string(direct),
+		"%PROXY%", string(proxy),
+		"%ACTION%", string(action),
+		"%DEFAULT%", string(defaultAction),
+	).Replace(pacTemplate)
+	return []byte(script), nil
+}
+
+func (c *PacConfig) GetNormalizedPath() string {
+	path := c.Path
+	if path == "" {
+		return "/proxy.pac"
+	}
+	if path[0] != '/' {
+		return "/" + path
+	}
+	return path
+}
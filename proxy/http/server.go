@@ -2,6 +2,7 @@ package http
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"encoding/base64"
 	"io"
@@ -101,6 +102,35 @@ func (s *Server) handleConnect(ctx context.Context, _ *http.Request, reader *buf
 	return nil
 }
 
+func (s *Server) handlePAC(ctx context.Context, request *http.Request, conn internet.Connection) error {
+	var port net.Port
+	if inbound := session.InboundFromContext(ctx); inbound != nil {
+		port = inbound.Gateway.Port
+	}
+	script, err := s.config.Pac.Generate(request.Host, port)
+	if err != nil {
+		return newError("failed to generate PAC script").Base(err).AtWarning()
+	}
+	response := &http.Response{
+		Status:        "OK",
+		StatusCode:    200,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header(make(map[string][]string)),
+		Body:          io.NopCloser(bytes.NewReader(script)),
+		ContentLength: int64(len(script)),
+		Close:         true,
+	}
+	response.Header.Set("Content-Type", "application/x-ns-proxy-autoconfig")
+	response.Header.Set("Connection", "close")
+	if request.Method == http.MethodHead {
+		response.Body = nil
+	}
+	newError("serving PAC script to ", conn.RemoteAddr()).WriteToLog(session.ExportIDToError(ctx))
+	return response.Write(conn)
+}
+
 func (s *Server) handlePlainHTTP(ctx context.Context, request *http.Request, writer io.Writer, dest net.Destination, dispatcher routing.Dispatcher) error {
 	if !s.config.AllowTransparent && request.URL.Host == "" {
 		response := &http.Response{
@@ -211,6 +241,9 @@ Start:
 		}
 		return trace
 	}
+	if s.config.Pac != nil && request.URL.Host == "" && request.URL.Path == s.config.Pac.GetNormalizedPath() && (request.Method == http.MethodGet || request.Method == http.MethodHead) {
+		return s.handlePAC(ctx, request, conn)
+	}
 	if len(s.config.Accounts) > 0 {
 		user, pass, ok := parseBasicAuth(request.Header.Get("Proxy-Authorization"))
 		if !ok || !s.config.HasAccount(user, pass) {
@@ -220,6 +253,9 @@ Start:
 			inbound.User.Email = user
 		}
 	}
+	if s.config.ConnectOnly && !strings.EqualFold(request.Method, "CONNECT") {
+		return common.Error2(conn.Write([]byte("HTTP/1.1 405 Method Not Allowed\r\nAllow: CONNECT\r\nConnection: close\r\n\r\n")))
+	}
 	newError("request to Method [", request.Method, "] Host [", request.Host, "] with URL [", request.URL, "]").WriteToLog(session.ExportIDToError(ctx))
 	if err := conn.SetReadDeadline(time.Time{}); err != nil {
 		newError("failed to clear read deadline").Base(err).WriteToLog(session.ExportIDToError(ctx))
@@ -0,0 +1,174 @@
+package http
+
+import (
+	"bufio"
+	"context"
+	"io"
+	gonet "net"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/vmessocket/vmessocket/common"
+	"github.com/vmessocket/vmessocket/common/net"
+	"github.com/vmessocket/vmessocket/common/session"
+	"github.com/vmessocket/vmessocket/transport"
+	"github.com/vmessocket/vmessocket/transport/pipe"
+)
+
+type testDispatcher struct {
+	destinations chan net.Destination
+}
+
+func (d *testDispatcher) Close() error {
+	return nil
+}
+
+func (d *testDispatcher) Dispatch(ctx context.Context, dest net.Destination) (*transport.Link, error) {
+	d.destinations <- dest
+	_, uplinkWriter := pipe.New(pipe.DiscardOverflow())
+	downlinkReader, downlinkWriter := pipe.New()
+	common.Must(downlinkWriter.Close())
+	return &transport.Link{
+		Reader: downlinkReader,
+		Writer: uplinkWriter,
+	}, nil
+}
+
+func (d *testDispatcher) Start() error {
+	return nil
+}
+
+func (d *testDispatcher) Type() interface{} {
+	return nil
+}
+
+func roundTrip(t *testing.T, config *ServerConfig, raw string) (*http.Response, *testDispatcher) {
+	t.Helper()
+	server, err := NewServer(context.Background(), config)
+	common.Must(err)
+	dispatcher := &testDispatcher{
+		destinations: make(chan net.Destination, 1),
+	}
+	client, conn := gonet.Pipe()
+	ctx := session.ContextWithInbound(context.Background(), &session.Inbound{
+		Gateway: net.TCPDestination(net.LocalHostIP, 8080),
+		Source:  net.TCPDestination(net.LocalHostIP, 50000),
+	})
+	done := make(chan error, 1)
+	go func() {
+		done <- server.Process(ctx, net.Network_TCP, conn, dispatcher)
+		conn.Close()
+	}()
+	go client.Write([]byte(raw))
+	request, err := http.ReadRequest(bufio.NewReader(strings.NewReader(raw)))
+	common.Must(err)
+	response, err := http.ReadResponse(bufio.NewReader(client), request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		client.Close()
+		<-done
+	})
+	return response, dispatcher
+}
+
+func TestPACScriptUsesListeningPort(t *testing.T) {
+	testCases := []struct {
+		host   string
+		config *PacConfig
+		proxy  string
+	}{
+		{
+			host:   "proxy.example.com",
+			config: &PacConfig{},
+			proxy:  "PROXY proxy.example.com:8080",
+		},
+		{
+			host:   "proxy.example.com:3128",
+			config: &PacConfig{},
+			proxy:  "PROXY proxy.example.com:8080",
+		},
+		{
+			host:   "[2001:db8::1]",
+			config: &PacConfig{},
+			proxy:  "PROXY [2001:db8::1]:8080",
+		},
+		{
+			host: "proxy.example.com",
+			config: &PacConfig{
+				ProxyAddress: "gateway.example.com",
+			},
+			proxy: "PROXY gateway.example.com:8080",
+		},
+		{
+			host: "proxy.example.com",
+			config: &PacConfig{
+				ProxyAddress: "gateway.example.com:3128",
+			},
+			proxy: "PROXY gateway.example.com:3128",
+		},
+	}
+	for _, tc := range testCases {
+		response, _ := roundTrip(t, &ServerConfig{Pac: tc.config}, "GET /proxy.pac HTTP/1.1\r\nHost: "+tc.host+"\r\n\r\n")
+		if response.StatusCode != http.StatusOK {
+			t.Fatal("unexpected status: ", response.Status)
+		}
+		if contentType := response.Header.Get("Content-Type"); contentType != "application/x-ns-proxy-autoconfig" {
+			t.Error("unexpected content type: ", contentType)
+		}
+		script, err := io.ReadAll(response.Body)
+		common.Must(err)
+		if !strings.Contains(string(script), `"`+tc.proxy+`"`) {
+			t.Error("PAC script for host ", tc.host, " does not contain ", tc.proxy, ":\n", string(script))
+		}
+	}
+}
+
+func TestPACScriptDomains(t *testing.T) {
+	config := &PacConfig{
+		Path:          "custom.pac",
+		DirectDomain:  []string{" .Example.COM", ""},
+		ProxyDomain:   []string{"blocked.example"},
+		DefaultDirect: true,
+	}
+	script, err := config.Generate("proxy.example.com", 8080)
+	common.Must(err)
+	for _, expected := range []string{
+		`var direct = ["example.com"];`,
+		`var proxy = ["blocked.example"];`,
+		`return "PROXY proxy.example.com:8080";`,
+		`return "DIRECT";` + "\n}",
+	} {
+		if !strings.Contains(string(script), expected) {
+			t.Error("PAC script does not contain ", expected, ":\n", string(script))
+		}
+	}
+	if path := config.GetNormalizedPath(); path != "/custom.pac" {
+		t.Error("unexpected PAC path: ", path)
+	}
+}
+
+func TestConnectOnly(t *testing.T) {
+	config := &ServerConfig{
+		ConnectOnly: true,
+	}
+	response, dispatcher := roundTrip(t, config, "GET http://example.com/ HTTP/1.1\r\nHost: example.com\r\n\r\n")
+	if response.StatusCode != http.StatusMethodNotAllowed {
+		t.Fatal("unexpected status for plain request: ", response.Status)
+	}
+	if allow := response.Header.Get("Allow"); allow != "CONNECT" {
+		t.Error("unexpected Allow header: ", allow)
+	}
+	if len(dispatcher.destinations) != 0 {
+		t.Error("plain request was dispatched")
+	}
+	response, dispatcher = roundTrip(t, config, "CONNECT example.com:443 HTTP/1.1\r\nHost: example.com:443\r\n\r\n")
+	if response.StatusCode != http.StatusOK {
+		t.Fatal("unexpected status for CONNECT: ", response.Status)
+	}
+	if dest := <-dispatcher.destinations; dest != net.TCPDestination(net.DomainAddress("example.com"), 443) {
+		t.Error("unexpected destination: ", dest)
+	}
+}
@@ -0,0 +1,108 @@
+package loopback
+
+import (
+	"context"
+
+	"github.com/vmessocket/vmessocket/common"
+	"github.com/vmessocket/vmessocket/common/buf"
+	"github.com/vmessocket/vmessocket/common/session"
+	"github.com/vmessocket/vmessocket/common/signal"
+	"github.com/vmessocket/vmessocket/common/task"
+	"github.com/vmessocket/vmessocket/core"
+	"github.com/vmessocket/vmessocket/features/routing"
+	"github.com/vmessocket/vmessocket/transport"
+	"github.com/vmessocket/vmessocket/transport/internet"
+)
+
+const defaultMaxHops = 8
+
+type Handler struct {
+	config     *Config
+	dispatcher routing.Dispatcher
+}
+
+func copyContent(content *session.Content) *session.Content {
+	c := new(session.Content)
+	if content == nil {
+		return c
+	}
+	*c = *content
+	c.Attributes = nil
+	for name, value := range content.Attributes {
+		c.SetAttribute(name, value)
+	}
+	return c
+}
+
+func (h *Handler) Init(config *Config, dispatcher routing.Dispatcher) error {
+	h.config = config
+	h.dispatcher = dispatcher
+	return nil
+}
+
+func (h *Handler) maxHops() uint32 {
+	if h.config.MaxHops > 0 {
+		return h.config.MaxHops
+	}
+	return defaultMaxHops
+}
+
+func (h *Handler) Process(ctx context.Context, link *transport.Link, _ internet.Dialer) error {
+	outbound := session.OutboundFromContext(ctx)
+	if outbound == nil || !outbound.Target.IsValid() {
+		return newError("target not specified.")
+	}
+	destination := outbound.Target
+	content := copyContent(session.ContentFromContext(ctx))
+	if content.Hops >= h.maxHops() {
+		return newError("loop detected: ", destination, " has been looped back ", content.Hops, " times")
+	}
+	content.Hops++
+	inbound := &session.Inbound{
+		Tag: h.config.InboundTag,
+	}
+	if origin := session.InboundFromContext(ctx); origin != nil {
+		inbound.Source = origin.Source
+		inbound.Gateway = origin.Gateway
+		inbound.User = origin.User
+	}
+	loopCtx := session.ContextWithContent(ctx, content)
+	loopCtx = session.ContextWithInbound(loopCtx, inbound)
+	newError("looping back ", destination, " to inbound [", h.config.InboundTag, "]").WriteToLog(session.ExportIDToError(ctx))
+	loopLink, err := h.dispatcher.Dispatch(loopCtx, destination)
+	if err != nil {
+		return newError("failed to dispatch request to ", destination).Base(err)
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	timer := signal.CancelAfterInactivity(ctx, cancel)
+	requestDone := func() error {
+		if err := buf.Copy(link.Reader, loopLink.Writer, buf.UpdateActivity(timer)); err != nil {
+			return newError("failed to process request").Base(err)
+		}
+		return nil
+	}
+	responseDone := func() error {
+		if err := buf.Copy(loopLink.Reader, link.Writer, buf.UpdateActivity(timer)); err != nil {
+			return newError("failed to process response").Base(err)
+		}
+		return nil
+	}
+	if err := task.Run(ctx, task.OnSuccess(requestDone, task.Close(loopLink.Writer)), responseDone); err != nil {
+		common.Interrupt(loopLink.Reader)
+		common.Interrupt(loopLink.Writer)
+		return newError("connection ends").Base(err)
+	}
+	return nil
+}
+
+func init() {
+	common.Must(common.RegisterConfig((*Config)(nil), func(ctx context.Context, config interface{}) (interface{}, error) {
+		h := new(Handler)
+		if err := core.RequireFeatures(ctx, func(d routing.Dispatcher) error {
+			return h.Init(config.(*Config), d)
+		}); err != nil {
+			return nil, err
+		}
+		return h, nil
+	}))
+}
@@ -0,0 +1,90 @@
+package loopback_test
+
+import (
+	"context"
+	"io"
+	gonet "net"
+	"testing"
+
+	"github.com/vmessocket/vmessocket/app/dispatcher"
+	"github.com/vmessocket/vmessocket/app/proxyman"
+	_ "github.com/vmessocket/vmessocket/app/proxyman/inbound"
+	_ "github.com/vmessocket/vmessocket/app/proxyman/outbound"
+	"github.com/vmessocket/vmessocket/common"
+	"github.com/vmessocket/vmessocket/common/net"
+	"github.com/vmessocket/vmessocket/common/serial"
+	"github.com/vmessocket/vmessocket/core"
+	"github.com/vmessocket/vmessocket/proxy/freedom"
+	"github.com/vmessocket/vmessocket/proxy/loopback"
+	_ "github.com/vmessocket/vmessocket/transport/internet/tcp"
+)
+
+func startEchoServer(t *testing.T) net.Destination {
+	t.Helper()
+	listener, err := gonet.Listen("tcp", "127.0.0.1:0")
+	common.Must(err)
+	t.Cleanup(func() {
+		listener.Close()
+	})
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				io.Copy(conn, conn)
+			}()
+		}
+	}()
+	return net.DestinationFromAddr(listener.Addr())
+}
+
+func TestLoopbackReachesTaggedOutbound(t *testing.T) {
+	dest := startEchoServer(t)
+	config := &core.Config{
+		App: []*serial.TypedMessage{
+			serial.ToTypedMessage(&dispatcher.Config{
+				Rule: []*dispatcher.RoutingRule{
+					{
+						InboundTag:  []string{"looped"},
+						OutboundTag: "direct",
+					},
+				},
+			}),
+			serial.ToTypedMessage(&proxyman.InboundConfig{}),
+			serial.ToTypedMessage(&proxyman.OutboundConfig{}),
+		},
+		Outbound: []*core.OutboundHandlerConfig{
+			{
+				ProxySettings: serial.ToTypedMessage(&loopback.Config{
+					InboundTag: "looped",
+					MaxHops:    1,
+				}),
+			},
+			{
+				Tag:           "direct",
+				ProxySettings: serial.ToTypedMessage(&freedom.Config{}),
+			},
+		},
+	}
+	instance, err := core.New(config)
+	common.Must(err)
+	common.Must(instance.Start())
+	defer instance.Close()
+	conn, err := core.Dial(context.Background(), instance, dest)
+	common.Must(err)
+	defer conn.Close()
+	payload := []byte("looped payload")
+	if _, err := conn.Write(payload); err != nil {
+		t.Fatal(err)
+	}
+	response := make([]byte, len(payload))
+	if _, err := io.ReadFull(conn, response); err != nil {
+		t.Fatal(err)
+	}
+	if string(response) != string(payload) {
+		t.Error("unexpected response: ", string(response))
+	}
+}
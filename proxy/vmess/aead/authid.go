@@ -9,12 +9,18 @@ import (
 	"errors"
 	"hash/crc32"
 	"io"
-	"math"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/vmessocket/vmessocket/common"
 )
 
+const (
+	authIDTimeTolerance = 120
+	hotDecoderCapacity  = 256
+)
+
 var (
 	ErrNotFound = errors.New("user do not exist")
 	ErrReplay   = errors.New("replayed request")
@@ -25,12 +31,17 @@ type AuthIDDecoder struct {
 }
 
 type AuthIDDecoderHolder struct {
-	decoders map[string]*AuthIDDecoderItem
+	access   sync.Mutex
+	decoders []*AuthIDDecoderItem
+	index    map[string]int
+	hot      atomic.Value
 }
 
 type AuthIDDecoderItem struct {
 	dec    *AuthIDDecoder
 	ticket interface{}
+	key    string
+	hot    uint32
 }
 
 func CreateAuthID(cmdKey []byte, time int64) [16]byte {
@@ -54,13 +65,18 @@ func NewAuthIDDecoder(cmdKey []byte) *AuthIDDecoder {
 }
 
 func NewAuthIDDecoderHolder() *AuthIDDecoderHolder {
-	return &AuthIDDecoderHolder{make(map[string]*AuthIDDecoderItem)}
+	h := &AuthIDDecoderHolder{
+		index: make(map[string]int),
+	}
+	h.hot.Store([]*AuthIDDecoderItem(nil))
+	return h
 }
 
 func NewAuthIDDecoderItem(key [16]byte, ticket interface{}) *AuthIDDecoderItem {
 	return &AuthIDDecoderItem{
 		dec:    NewAuthIDDecoder(key[:]),
 		ticket: ticket,
+		key:    string(key[:]),
 	}
 }
 
@@ -73,7 +89,14 @@ func NewCipherFromKey(cmdKey []byte) cipher.Block {
 }
 
 func (a *AuthIDDecoderHolder) AddUser(key [16]byte, ticket interface{}) {
-	a.decoders[string(key[:])] = NewAuthIDDecoderItem(key, ticket)
+	item := NewAuthIDDecoderItem(key, ticket)
+	if idx, found := a.index[item.key]; found {
+		a.dropHot(a.decoders[idx])
+		a.decoders[idx] = item
+		return
+	}
+	a.index[item.key] = len(a.decoders)
+	a.decoders = append(a.decoders, item)
 }
 
 func (aidd *AuthIDDecoder) Decode(data [16]byte) (int64, uint32, int32, []byte) {
@@ -88,23 +111,88 @@ func (aidd *AuthIDDecoder) Decode(data [16]byte) (int64, uint32, int32, []byte)
 	return t, zero, rand, data[:]
 }
 
-func (a *AuthIDDecoderHolder) Match(authID [16]byte) (interface{}, error) {
-	for _, v := range a.decoders {
-		t, z, _, d := v.dec.Decode(authID)
-		if z != crc32.ChecksumIEEE(d[:12]) {
-			continue
+func (a *AuthIDDecoderHolder) dropHot(item *AuthIDDecoderItem) {
+	if atomic.LoadUint32(&item.hot) == 0 {
+		return
+	}
+	a.access.Lock()
+	defer a.access.Unlock()
+	hot := a.hot.Load().([]*AuthIDDecoderItem)
+	updated := make([]*AuthIDDecoderItem, 0, len(hot))
+	for _, v := range hot {
+		if v != item {
+			updated = append(updated, v)
 		}
-		if t < 0 {
-			continue
+	}
+	atomic.StoreUint32(&item.hot, 0)
+	a.hot.Store(updated)
+}
+
+func (aidd *AuthIDDecoder) match(authID [16]byte, now int64) bool {
+	var data [16]byte
+	aidd.s.Decrypt(data[:], authID[:])
+	if binary.BigEndian.Uint32(data[12:]) != crc32.ChecksumIEEE(data[:12]) {
+		return false
+	}
+	t := int64(binary.BigEndian.Uint64(data[:8]))
+	if t < 0 {
+		return false
+	}
+	diff := t - now
+	if diff < 0 {
+		diff = -diff
+	}
+	return diff <= authIDTimeTolerance
+}
+
+func (a *AuthIDDecoderHolder) Match(authID [16]byte) (interface{}, error) {
+	now := time.Now().Unix()
+	hot := a.hot.Load().([]*AuthIDDecoderItem)
+	for _, v := range hot {
+		if v.dec.match(authID, now) {
+			return v.ticket, nil
 		}
-		if math.Abs(math.Abs(float64(t))-float64(time.Now().Unix())) > 120 {
-			continue
+	}
+	for _, v := range a.decoders {
+		if v.dec.match(authID, now) {
+			a.promote(v)
+			return v.ticket, nil
 		}
-		return v.ticket, nil
 	}
 	return nil, ErrNotFound
 }
 
+func (a *AuthIDDecoderHolder) promote(item *AuthIDDecoderItem) {
+	if atomic.LoadUint32(&item.hot) == 1 {
+		return
+	}
+	a.access.Lock()
+	defer a.access.Unlock()
+	if !atomic.CompareAndSwapUint32(&item.hot, 0, 1) {
+		return
+	}
+	hot := a.hot.Load().([]*AuthIDDecoderItem)
+	if len(hot) >= hotDecoderCapacity {
+		atomic.StoreUint32(&hot[len(hot)-1].hot, 0)
+		hot = hot[:len(hot)-1]
+	}
+	updated := make([]*AuthIDDecoderItem, 0, len(hot)+1)
+	updated = append(updated, item)
+	updated = append(updated, hot...)
+	a.hot.Store(updated)
+}
+
 func (a *AuthIDDecoderHolder) RemoveUser(key [16]byte) {
-	delete(a.decoders, string(key[:]))
+	idx, found := a.index[string(key[:])]
+	if !found {
+		return
+	}
+	item := a.decoders[idx]
+	a.dropHot(item)
+	last := len(a.decoders) - 1
+	a.decoders[idx] = a.decoders[last]
+	a.index[a.decoders[idx].key] = idx
+	a.decoders[last] = nil
+	a.decoders = a.decoders[:last]
+	delete(a.index, item.key)
 }
@@ -0,0 +1,148 @@
+package aead_test
+
+import (
+	"crypto/rand"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/vmessocket/vmessocket/common"
+	. "github.com/vmessocket/vmessocket/proxy/vmess/aead"
+)
+
+func newKey() [16]byte {
+	var key [16]byte
+	common.Must2(rand.Read(key[:]))
+	return key
+}
+
+func newHolder(count int) (*AuthIDDecoderHolder, [][16]byte) {
+	holder := NewAuthIDDecoderHolder()
+	keys := make([][16]byte, count)
+	for i := range keys {
+		keys[i] = newKey()
+		holder.AddUser(keys[i], i)
+	}
+	return holder, keys
+}
+
+func BenchmarkAuthIDDecoderHolder(b *testing.B) {
+	for _, users := range []int{10000, 100000} {
+		holder, keys := newHolder(users)
+		now := time.Now().Unix()
+		authIDs := make([][16]byte, 1024)
+		for i := range authIDs {
+			authIDs[i] = CreateAuthID(keys[(i*7919)%users][:], now)
+		}
+		b.Run(fmt.Sprintf("users=%d/cold", users), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := holder.Match(authIDs[i%len(authIDs)]); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("users=%d/hot", users), func(b *testing.B) {
+			authID := authIDs[0]
+			common.Must2(holder.Match(authID))
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := holder.Match(authID); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("users=%d/miss", users), func(b *testing.B) {
+			authID := newKey()
+			for i := 0; i < b.N; i++ {
+				if _, err := holder.Match(authID); err != ErrNotFound {
+					b.Fatal("unexpected match")
+				}
+			}
+		})
+	}
+}
+
+func TestAuthIDDecoderHolderMatch(t *testing.T) {
+	holder, keys := newHolder(4)
+	now := time.Now().Unix()
+	unknown := newKey()
+	tampered := CreateAuthID(keys[1][:], now)
+	tampered[15] ^= 1
+	testCases := []struct {
+		name   string
+		authID [16]byte
+		ticket interface{}
+		err    error
+	}{
+		{
+			name:   "current",
+			authID: CreateAuthID(keys[0][:], now),
+			ticket: 0,
+		},
+		{
+			name:   "within tolerance",
+			authID: CreateAuthID(keys[3][:], now-100),
+			ticket: 3,
+		},
+		{
+			name:   "expired",
+			authID: CreateAuthID(keys[2][:], now-600),
+			err:    ErrNotFound,
+		},
+		{
+			name:   "future",
+			authID: CreateAuthID(keys[2][:], now+600),
+			err:    ErrNotFound,
+		},
+		{
+			name:   "unknown key",
+			authID: CreateAuthID(unknown[:], now),
+			err:    ErrNotFound,
+		},
+		{
+			name:   "tampered",
+			authID: tampered,
+			err:    ErrNotFound,
+		},
+		{
+			name:   "random",
+			authID: newKey(),
+			err:    ErrNotFound,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ticket, err := holder.Match(tc.authID)
+			if err != tc.err {
+				t.Fatalf("expected error %v, got %v", tc.err, err)
+			}
+			if ticket != tc.ticket {
+				t.Fatalf("expected ticket %v, got %v", tc.ticket, ticket)
+			}
+		})
+	}
+}
+
+func TestAuthIDDecoderHolderRemoveUser(t *testing.T) {
+	holder, keys := newHolder(3)
+	unknown := newKey()
+	now := time.Now().Unix()
+	for i := 0; i < 2; i++ {
+		common.Must2(holder.Match(CreateAuthID(keys[0][:], now)))
+	}
+	holder.RemoveUser(keys[0])
+	if _, err := holder.Match(CreateAuthID(keys[0][:], now)); err != ErrNotFound {
+		t.Fatal("removed user still matches: ", err)
+	}
+	for i := 1; i < 3; i++ {
+		ticket, err := holder.Match(CreateAuthID(keys[i][:], now))
+		if err != nil || ticket != i {
+			t.Fatalf("user %d: got %v, %v", i, ticket, err)
+		}
+	}
+	holder.AddUser(keys[1], "replaced")
+	if ticket, err := holder.Match(CreateAuthID(keys[1][:], now)); err != nil || ticket != "replaced" {
+		t.Fatalf("re-added user: got %v, %v", ticket, err)
+	}
+	holder.RemoveUser(unknown)
+}
@@ -330,8 +330,10 @@ func (c *ClientSession) EncodeRequestHeader(header *protocol.RequestHeader, writ
 	paddingLen := dice.Roll(16)
 	security := byte(paddingLen<<4) | byte(header.Security)
 	common.Must2(buffer.Write([]byte{security, byte(0), byte(header.Command)}))
-	if err := addrParser.WriteAddressPort(buffer, header.Address, header.Port); err != nil {
-		return newError("failed to writer address and port").Base(err)
+	if header.Command != protocol.RequestCommandMux {
+		if err := addrParser.WriteAddressPort(buffer, header.Address, header.Port); err != nil {
+			return newError("failed to writer address and port").Base(err)
+		}
 	}
 	if paddingLen > 0 {
 		common.Must2(buffer.ReadFullFrom(rand.Reader, int32(paddingLen)))
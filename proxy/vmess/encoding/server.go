@@ -19,6 +19,7 @@ import (
 	"github.com/vmessocket/vmessocket/common/buf"
 	"github.com/vmessocket/vmessocket/common/crypto"
 	"github.com/vmessocket/vmessocket/common/drain"
+	"github.com/vmessocket/vmessocket/common/net"
 	"github.com/vmessocket/vmessocket/common/protocol"
 	"github.com/vmessocket/vmessocket/common/task"
 	"github.com/vmessocket/vmessocket/proxy/vmess"
@@ -36,6 +37,7 @@ type ServerSession struct {
 	responseHeader  byte
 	isAEADRequest   bool
 	isAEADForced    bool
+	drainDisabled   bool
 }
 
 type SessionHistory struct {
@@ -178,6 +180,9 @@ func (s *ServerSession) DecodeRequestHeader(reader io.Reader) (*protocol.Request
 	if err != nil {
 		return nil, newError("failed to initialize drainer").Base(err)
 	}
+	if s.drainDisabled {
+		drainer = drain.NewNopDrainer()
+	}
 	drainConnection := func(e error) error {
 		drainer.AcknowledgeReceive(int(buffer.Len()))
 		return drain.WithError(drainer, reader, e)
@@ -209,13 +214,13 @@ func (s *ServerSession) DecodeRequestHeader(reader io.Reader) (*protocol.Request
 		decryptor = bytes.NewReader(aeadData)
 		s.isAEADRequest = true
 	case errorAEAD == vmessaead.ErrNotFound:
+		if s.isAEADForced {
+			return nil, drainConnection(newError("invalid user: VMessAEAD is enforced and a non VMessAEAD connection is received."))
+		}
 		userLegacy, timestamp, valid, userValidationError := s.userValidator.Get(buffer.Bytes())
 		if !valid || userValidationError != nil {
 			return nil, drainConnection(newError("invalid user").Base(userValidationError))
 		}
-		if s.isAEADForced {
-			return nil, drainConnection(newError("invalid user: VMessAEAD is enforced and a non VMessAEAD connection is received."))
-		}
 		if s.userValidator.ShouldShowLegacyWarn() {
 			newError("Critical Warning: potentially invalid user: a non VMessAEAD connection is received. From 2022 Jan 1st, this kind of connection will be rejected by default. You should update or replace your client software now. This message will not be shown for further violation on this inbound.").AtWarning().WriteToLog()
 		}
@@ -263,6 +268,9 @@ func (s *ServerSession) DecodeRequestHeader(reader io.Reader) (*protocol.Request
 			request.Address = addr
 			request.Port = port
 		}
+	case protocol.RequestCommandMux:
+		request.Address = net.DomainAddress("v1.mux.cool")
+		request.Port = 0
 	}
 	if paddingLen > 0 {
 		if _, err := buffer.ReadFullFrom(decryptor, int32(paddingLen)); err != nil {
@@ -448,3 +456,7 @@ func (h *SessionHistory) removeExpiredEntries() error {
 func (s *ServerSession) SetAEADForced(isAEADForced bool) {
 	s.isAEADForced = isAEADForced
 }
+
+func (s *ServerSession) SetDrainDisabled(isDrainDisabled bool) {
+	s.drainDisabled = isDrainDisabled
+}
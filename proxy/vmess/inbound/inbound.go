@@ -1,8 +1,10 @@
 package inbound
 
 import (
+	"bytes"
 	"context"
 	"io"
+	"math"
 	"strings"
 	"sync"
 	"time"
@@ -12,7 +14,6 @@ import (
 	"github.com/vmessocket/vmessocket/common/errors"
 	"github.com/vmessocket/vmessocket/common/log"
 	"github.com/vmessocket/vmessocket/common/net"
-	"github.com/vmessocket/vmessocket/common/platform"
 	"github.com/vmessocket/vmessocket/common/protocol"
 	"github.com/vmessocket/vmessocket/common/session"
 	"github.com/vmessocket/vmessocket/common/signal"
@@ -26,10 +27,7 @@ import (
 	"github.com/vmessocket/vmessocket/transport/internet"
 )
 
-var (
-	aeadForced     = false
-	aeadForced2022 = false
-)
+const maxFallbackPrefix = 16 + 18 + 8 + math.MaxUint16 + 16 + buf.Size
 
 type Handler struct {
 	inboundHandlerManager feature_inbound.Manager
@@ -38,6 +36,22 @@ type Handler struct {
 	detours               *DetourConfig
 	sessionHistory        *encoding.SessionHistory
 	secure                bool
+	fallback              *net.Destination
+	userFile              *userFileWatcher
+	legacyDisabled        bool
+	aeadForced            bool
+	allowedSecurity       []protocol.SecurityType
+	noTerminationSignal   bool
+}
+
+type packetReader struct {
+	reader buf.Reader
+	dest   net.Destination
+}
+
+type prefixRecorder struct {
+	bytes.Buffer
+	truncated bool
 }
 
 type userByEmail struct {
@@ -45,6 +59,7 @@ type userByEmail struct {
 	cache           map[string]*protocol.MemoryUser
 	defaultLevel    uint32
 	defaultAlterIDs uint16
+	legacyDisabled  bool
 }
 
 func isInsecureEncryption(s protocol.SecurityType) bool {
@@ -57,9 +72,20 @@ func New(ctx context.Context, config *Config) (*Handler, error) {
 		inboundHandlerManager: v.GetFeature(feature_inbound.ManagerType()).(feature_inbound.Manager),
 		clients:               vmess.NewTimedUserValidator(protocol.DefaultIDHash),
 		detours:               config.Detour,
-		usersByEmail:          newUserByEmail(config.GetDefaultValue()),
+		usersByEmail:          newUserByEmail(config.GetDefaultValue(), config.DisableLegacy),
 		sessionHistory:        encoding.NewSessionHistory(),
 		secure:                config.SecureEncryptionOnly,
+		legacyDisabled:        config.DisableLegacy,
+		aeadForced:            config.AeadMode != AEADMode_AEAD_MODE_OPTIONAL,
+		allowedSecurity:       config.AllowedSecurity,
+		noTerminationSignal:   config.NoTerminationSignal,
+	}
+	if handler.legacyDisabled {
+		handler.clients.DisableLegacy()
+	}
+	if config.Fallback != nil {
+		dest := config.Fallback.AsDestination()
+		handler.fallback = &dest
 	}
 	for _, user := range config.User {
 		mUser, err := user.ToMemoryUser()
@@ -70,18 +96,36 @@ func New(ctx context.Context, config *Config) (*Handler, error) {
 			return nil, newError("failed to initiate user").Base(err)
 		}
 	}
+	if config.UserFile != nil && config.UserFile.Path != "" {
+		handler.userFile = newUserFileWatcher(handler, config.UserFile)
+	}
 	return handler, nil
 }
 
-func newUserByEmail(config *DefaultConfig) *userByEmail {
+func newUserByEmail(config *DefaultConfig, legacyDisabled bool) *userByEmail {
 	return &userByEmail{
 		cache:           make(map[string]*protocol.MemoryUser),
 		defaultLevel:    config.Level,
 		defaultAlterIDs: uint16(config.AlterId),
+		legacyDisabled:  legacyDisabled,
 	}
 }
 
-func transferResponse(timer signal.ActivityUpdater, session *encoding.ServerSession, request *protocol.RequestHeader, response *protocol.ResponseHeader, input buf.Reader, output *buf.BufferedWriter) error {
+func stripAlterIDs(user *protocol.MemoryUser) *protocol.MemoryUser {
+	account, ok := user.Account.(*vmess.MemoryAccount)
+	if !ok || len(account.AlterIDs) == 0 {
+		return user
+	}
+	stripped := *account
+	stripped.AlterIDs = nil
+	return &protocol.MemoryUser{
+		Level:   user.Level,
+		Email:   user.Email,
+		Account: &stripped,
+	}
+}
+
+func transferResponse(timer signal.ActivityUpdater, session *encoding.ServerSession, request *protocol.RequestHeader, response *protocol.ResponseHeader, input buf.Reader, output *buf.BufferedWriter, noTerminationSignal bool) error {
 	session.EncodeResponseHeader(response, output)
 	bodyWriter := session.EncodeResponseBody(request, output)
 	{
@@ -100,7 +144,7 @@ func transferResponse(timer signal.ActivityUpdater, session *encoding.ServerSess
 		return err
 	}
 	account := request.User.Account.(*vmess.MemoryAccount)
-	if request.Option.Has(protocol.RequestOptionChunkStream) && !account.NoTerminationSignal {
+	if request.Option.Has(protocol.RequestOptionChunkStream) && !account.NoTerminationSignal && !noTerminationSignal {
 		if err := bodyWriter.WriteMultiBuffer(buf.MultiBuffer{}); err != nil {
 			return err
 		}
@@ -125,6 +169,9 @@ func (v *userByEmail) addNoLock(u *protocol.MemoryUser) bool {
 }
 
 func (h *Handler) AddUser(ctx context.Context, user *protocol.MemoryUser) error {
+	if h.legacyDisabled {
+		user = stripAlterIDs(user)
+	}
 	if len(user.Email) > 0 && !h.usersByEmail.Add(user) {
 		return newError("User ", user.Email, " already exists.")
 	}
@@ -132,6 +179,9 @@ func (h *Handler) AddUser(ctx context.Context, user *protocol.MemoryUser) error
 }
 
 func (h *Handler) Close() error {
+	if h.userFile != nil {
+		h.userFile.Close()
+	}
 	return errors.Combine(
 		h.clients.Close(),
 		h.sessionHistory.Close(),
@@ -190,6 +240,9 @@ func (v *userByEmail) Get(email string) (*protocol.MemoryUser, bool) {
 			Email:   email,
 			Account: account,
 		}
+		if v.legacyDisabled {
+			user = stripAlterIDs(user)
+		}
 		v.cache[email] = user
 	}
 	return user, found
@@ -203,14 +256,76 @@ func (h *Handler) GetUser(email string) *protocol.MemoryUser {
 	return user
 }
 
+func (h *Handler) handleFallback(ctx context.Context, connection internet.Connection, payload []byte) error {
+	if err := connection.SetReadDeadline(time.Time{}); err != nil {
+		newError("unable to set back read deadline").Base(err).WriteToLog(session.ExportIDToError(ctx))
+	}
+	conn, err := internet.DialSystem(ctx, *h.fallback, nil)
+	if err != nil {
+		return newError("failed to dial fallback ", *h.fallback).Base(err)
+	}
+	defer conn.Close()
+	ctx, cancel := context.WithCancel(ctx)
+	timer := signal.CancelAfterInactivity(ctx, cancel)
+	requestDone := func() error {
+		if _, err := conn.Write(payload); err != nil {
+			return newError("failed to replay request to fallback").Base(err)
+		}
+		if err := buf.Copy(buf.NewReader(connection), buf.NewWriter(conn), buf.UpdateActivity(timer)); err != nil {
+			return newError("failed to transfer request to fallback").Base(err)
+		}
+		if cw, ok := conn.(interface{ CloseWrite() error }); ok {
+			cw.CloseWrite()
+		}
+		return nil
+	}
+	responseDone := func() error {
+		if err := buf.Copy(buf.NewReader(conn), buf.NewWriter(connection), buf.UpdateActivity(timer)); err != nil {
+			return newError("failed to transfer response from fallback").Base(err)
+		}
+		return nil
+	}
+	if err := task.Run(ctx, requestDone, responseDone); err != nil {
+		return newError("fallback connection ends").Base(err)
+	}
+	return nil
+}
+
+func (v *userByEmail) Has(email string) bool {
+	email = strings.ToLower(email)
+	v.Lock()
+	defer v.Unlock()
+	_, found := v.cache[email]
+	return found
+}
+
+func (h *Handler) isSecurityAllowed(s protocol.SecurityType) bool {
+	if len(h.allowedSecurity) == 0 {
+		return true
+	}
+	for _, allowed := range h.allowedSecurity {
+		if allowed == s {
+			return true
+		}
+	}
+	return false
+}
+
 func (*Handler) Network() []net.Network {
 	return []net.Network{net.Network_TCP, net.Network_UNIX}
 }
 
 func (h *Handler) Process(ctx context.Context, network net.Network, connection internet.Connection, dispatcher routing.Dispatcher) error {
-	reader := &buf.BufferedReader{Reader: buf.NewReader(connection)}
+	var recorded *prefixRecorder
+	var rawReader io.Reader = connection
+	if h.fallback != nil {
+		recorded = new(prefixRecorder)
+		rawReader = io.TeeReader(connection, recorded)
+	}
+	reader := &buf.BufferedReader{Reader: buf.NewReader(rawReader)}
 	svrSession := encoding.NewServerSession(h.clients, h.sessionHistory)
-	svrSession.SetAEADForced(aeadForced)
+	svrSession.SetAEADForced(h.aeadForced)
+	svrSession.SetDrainDisabled(h.fallback != nil)
 	request, err := svrSession.DecodeRequestHeader(reader)
 	if err != nil {
 		if errors.Cause(err) != io.EOF {
@@ -220,10 +335,20 @@ func (h *Handler) Process(ctx context.Context, network net.Network, connection i
 				Status: log.AccessRejected,
 				Reason: err,
 			})
+			if h.fallback != nil && recorded.truncated {
+				newError("invalid request from ", connection.RemoteAddr(), " is too long to fall back").AtInfo().WriteToLog(session.ExportIDToError(ctx))
+			}
+			if h.fallback != nil && !recorded.truncated && recorded.Len() > 0 {
+				newError("invalid request from ", connection.RemoteAddr(), ", fallback to ", *h.fallback).Base(err).AtInfo().WriteToLog(session.ExportIDToError(ctx))
+				return h.handleFallback(ctx, connection, recorded.Bytes())
+			}
 			err = newError("invalid request from ", connection.RemoteAddr()).Base(err).AtInfo()
 		}
 		return err
 	}
+	if h.fallback != nil {
+		reader.Reader = buf.NewReader(connection)
+	}
 	if h.secure && isInsecureEncryption(request.Security) {
 		log.Record(&log.AccessMessage{
 			From:   connection.RemoteAddr(),
@@ -234,6 +359,19 @@ func (h *Handler) Process(ctx context.Context, network net.Network, connection i
 		})
 		return newError("client is using insecure encryption: ", request.Security)
 	}
+	if !h.isSecurityAllowed(request.Security) {
+		log.Record(&log.AccessMessage{
+			From:   connection.RemoteAddr(),
+			To:     "",
+			Status: log.AccessRejected,
+			Reason: "Security type not allowed",
+			Email:  request.User.Email,
+		})
+		return newError("client is using disallowed security type: ", request.Security)
+	}
+	if request.Command == protocol.RequestCommandUDP && !request.Option.Has(protocol.RequestOptionChunkStream) {
+		return newError("UDP request without chunk stream from ", connection.RemoteAddr())
+	}
 	ctx = log.ContextWithAccessMessage(ctx, &log.AccessMessage{
 		From:   connection.RemoteAddr(),
 		To:     request.Destination(),
@@ -258,6 +396,12 @@ func (h *Handler) Process(ctx context.Context, network net.Network, connection i
 	}
 	requestDone := func() error {
 		bodyReader := svrSession.DecodeRequestBody(request, reader)
+		if request.Command == protocol.RequestCommandUDP {
+			bodyReader = &packetReader{
+				reader: bodyReader,
+				dest:   request.Destination(),
+			}
+		}
 		if err := buf.Copy(bodyReader, link.Writer, buf.UpdateActivity(timer)); err != nil {
 			return newError("failed to transfer request").Base(err)
 		}
@@ -269,7 +413,7 @@ func (h *Handler) Process(ctx context.Context, network net.Network, connection i
 		response := &protocol.ResponseHeader{
 			Command: h.generateCommand(ctx, request),
 		}
-		return transferResponse(timer, svrSession, request, response, link.Reader, writer)
+		return transferResponse(timer, svrSession, request, response, link.Reader, writer, h.noTerminationSignal)
 	}
 	requestDonePost := task.OnSuccess(requestDone, task.Close(link.Writer))
 	if err := task.Run(ctx, requestDonePost, responseDone); err != nil {
@@ -280,6 +424,14 @@ func (h *Handler) Process(ctx context.Context, network net.Network, connection i
 	return nil
 }
 
+func (r *packetReader) ReadMultiBuffer() (buf.MultiBuffer, error) {
+	mb, err := r.reader.ReadMultiBuffer()
+	for _, b := range mb {
+		net.SetPacketDestination(b, r.dest)
+	}
+	return mb, err
+}
+
 func (v *userByEmail) Remove(email string) bool {
 	email = strings.ToLower(email)
 	v.Lock()
@@ -302,17 +454,27 @@ func (h *Handler) RemoveUser(ctx context.Context, email string) error {
 	return nil
 }
 
+func (h *Handler) Start() error {
+	if h.userFile != nil {
+		return h.userFile.Start()
+	}
+	return nil
+}
+
+func (r *prefixRecorder) Write(p []byte) (int, error) {
+	if r.truncated {
+		return len(p), nil
+	}
+	if r.Len()+len(p) > maxFallbackPrefix {
+		r.truncated = true
+		r.Buffer = bytes.Buffer{}
+		return len(p), nil
+	}
+	return r.Buffer.Write(p)
+}
+
 func init() {
 	common.Must(common.RegisterConfig((*Config)(nil), func(ctx context.Context, config interface{}) (interface{}, error) {
 		return New(ctx, config.(*Config))
 	}))
-	defaultFlagValue := "true_by_default_2022"
-	isAeadForced := platform.NewEnvFlag("vmessocket.vmess.aead.forced").GetValue(func() string { return defaultFlagValue })
-	if isAeadForced == "true" {
-		aeadForced = true
-	}
-	if isAeadForced == "true_by_default_2022" {
-		aeadForced = true
-		aeadForced2022 = true
-	}
 }
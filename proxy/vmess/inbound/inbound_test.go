@@ -0,0 +1,261 @@
+package inbound_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	gonet "net"
+	"testing"
+	"time"
+
+	"github.com/vmessocket/vmessocket/app/dispatcher"
+	"github.com/vmessocket/vmessocket/app/proxyman"
+	_ "github.com/vmessocket/vmessocket/app/proxyman/inbound"
+	_ "github.com/vmessocket/vmessocket/app/proxyman/outbound"
+	"github.com/vmessocket/vmessocket/common"
+	"github.com/vmessocket/vmessocket/common/buf"
+	"github.com/vmessocket/vmessocket/common/net"
+	"github.com/vmessocket/vmessocket/common/protocol"
+	"github.com/vmessocket/vmessocket/common/serial"
+	"github.com/vmessocket/vmessocket/common/session"
+	"github.com/vmessocket/vmessocket/common/uuid"
+	"github.com/vmessocket/vmessocket/core"
+	"github.com/vmessocket/vmessocket/features/routing"
+	"github.com/vmessocket/vmessocket/proxy/freedom"
+	"github.com/vmessocket/vmessocket/proxy/vmess"
+	"github.com/vmessocket/vmessocket/proxy/vmess/inbound"
+	"github.com/vmessocket/vmessocket/proxy/vmess/outbound"
+	"github.com/vmessocket/vmessocket/transport"
+	"github.com/vmessocket/vmessocket/transport/internet"
+	_ "github.com/vmessocket/vmessocket/transport/internet/udp"
+	"github.com/vmessocket/vmessocket/transport/pipe"
+)
+
+type pipeDialer struct {
+	serve func(gonet.Conn)
+}
+
+type stampRecorder struct {
+	routing.Dispatcher
+	stamps chan net.Destination
+}
+
+type stampWriter struct {
+	buf.Writer
+	stamps chan net.Destination
+}
+
+func startUDPEchoServer(t *testing.T) net.Destination {
+	t.Helper()
+	conn, err := gonet.ListenPacket("udp", "127.0.0.1:0")
+	common.Must(err)
+	t.Cleanup(func() {
+		conn.Close()
+	})
+	go func() {
+		b := make([]byte, 2048)
+		for {
+			n, addr, err := conn.ReadFrom(b)
+			if err != nil {
+				return
+			}
+			conn.WriteTo(b[:n], addr)
+		}
+	}()
+	return net.DestinationFromAddr(conn.LocalAddr())
+}
+
+func startInstance(t *testing.T) *core.Instance {
+	t.Helper()
+	instance, err := core.New(&core.Config{
+		App: []*serial.TypedMessage{
+			serial.ToTypedMessage(&dispatcher.Config{}),
+			serial.ToTypedMessage(&proxyman.InboundConfig{}),
+			serial.ToTypedMessage(&proxyman.OutboundConfig{}),
+		},
+		Outbound: []*core.OutboundHandlerConfig{
+			{
+				ProxySettings: serial.ToTypedMessage(&freedom.Config{}),
+			},
+		},
+	})
+	common.Must(err)
+	common.Must(instance.Start())
+	t.Cleanup(func() {
+		instance.Close()
+	})
+	return instance
+}
+
+func (d *pipeDialer) Address() net.Address {
+	return nil
+}
+
+func (d *pipeDialer) Dial(ctx context.Context, dest net.Destination) (internet.Connection, error) {
+	client, server := gonet.Pipe()
+	go d.serve(server)
+	return client, nil
+}
+
+func (r *stampRecorder) Dispatch(ctx context.Context, dest net.Destination) (*transport.Link, error) {
+	link, err := r.Dispatcher.Dispatch(ctx, dest)
+	if err != nil {
+		return nil, err
+	}
+	link.Writer = &stampWriter{Writer: link.Writer, stamps: r.stamps}
+	return link, nil
+}
+
+func (w *stampWriter) WriteMultiBuffer(mb buf.MultiBuffer) error {
+	for _, b := range mb {
+		dest, _ := net.PacketDestination(b)
+		w.stamps <- dest
+	}
+	return w.Writer.WriteMultiBuffer(mb)
+}
+
+func TestFallbackReplaysBadHandshake(t *testing.T) {
+	listener, err := gonet.Listen("tcp", "127.0.0.1:0")
+	common.Must(err)
+	defer listener.Close()
+	received := make(chan []byte, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		b, _ := io.ReadAll(conn)
+		received <- b
+		conn.Write([]byte("HTTP/1.1 400 Bad Request\r\n\r\n"))
+	}()
+	fallback := net.DestinationFromAddr(listener.Addr())
+	server, err := core.CreateObject(startInstance(t), &inbound.Config{
+		Fallback: &net.Endpoint{
+			Network: net.Network_TCP,
+			Address: net.NewIPOrDomain(fallback.Address),
+			Port:    uint32(fallback.Port),
+		},
+	})
+	common.Must(err)
+	request := []byte("GET /index.html HTTP/1.1\r\nHost: example.com\r\n")
+	for len(request) < 3*buf.Size {
+		request = append(request, "X-Padding: 0123456789abcdef0123456789abcdef\r\n"...)
+	}
+	request = append(request, "\r\n"...)
+	front, err := gonet.Listen("tcp", "127.0.0.1:0")
+	common.Must(err)
+	defer front.Close()
+	done := make(chan error, 1)
+	go func() {
+		conn, err := front.Accept()
+		if err != nil {
+			done <- err
+			return
+		}
+		defer conn.Close()
+		done <- server.(*inbound.Handler).Process(context.Background(), net.Network_TCP, conn, nil)
+	}()
+	client, err := gonet.Dial("tcp", front.Addr().String())
+	common.Must(err)
+	defer client.Close()
+	common.Must2(client.Write(request))
+	common.Must(client.(*gonet.TCPConn).CloseWrite())
+	response, err := io.ReadAll(client)
+	common.Must(err)
+	if string(response) != "HTTP/1.1 400 Bad Request\r\n\r\n" {
+		t.Errorf("unexpected fallback response %q", response)
+	}
+	if b := <-received; !bytes.Equal(b, request) {
+		t.Errorf("fallback received %d bytes, sent %d", len(b), len(request))
+	}
+	if err := <-done; err != nil {
+		t.Error(err)
+	}
+}
+
+func TestVMessUDPEndToEnd(t *testing.T) {
+	echo := startUDPEchoServer(t)
+	id := uuid.New()
+	account := serial.ToTypedMessage(&vmess.Account{
+		Id: id.String(),
+		SecuritySettings: &protocol.SecurityConfig{
+			Type: protocol.SecurityType_AES128_GCM,
+		},
+	})
+	instance := startInstance(t)
+	server, err := core.CreateObject(instance, &inbound.Config{
+		User: []*protocol.User{
+			{
+				Email:   "udp@example.com",
+				Account: account,
+			},
+		},
+	})
+	common.Must(err)
+	client, err := core.CreateObject(instance, &outbound.Config{
+		Receiver: []*protocol.ServerEndpoint{
+			{
+				Address: net.NewIPOrDomain(net.LocalHostIP),
+				Port:    10086,
+				User: []*protocol.User{
+					{
+						Email:   "udp@example.com",
+						Account: account,
+					},
+				},
+			},
+		},
+	})
+	common.Must(err)
+	recorder := &stampRecorder{
+		Dispatcher: instance.GetFeature(routing.DispatcherType()).(routing.Dispatcher),
+		stamps:     make(chan net.Destination, 16),
+	}
+	dialer := &pipeDialer{
+		serve: func(conn gonet.Conn) {
+			defer conn.Close()
+			ctx := session.ContextWithInbound(context.Background(), &session.Inbound{
+				Source:  net.TCPDestination(net.LocalHostIP, 50000),
+				Gateway: net.TCPDestination(net.LocalHostIP, 10086),
+				Tag:     "vmess",
+			})
+			server.(*inbound.Handler).Process(ctx, net.Network_TCP, conn, recorder)
+		},
+	}
+	uplinkReader, uplinkWriter := pipe.New()
+	downlinkReader, downlinkWriter := pipe.New()
+	ctx, cancel := context.WithCancel(session.ContextWithOutbound(context.Background(), &session.Outbound{
+		Target: echo,
+	}))
+	defer cancel()
+	go client.(*outbound.Handler).Process(ctx, &transport.Link{Reader: uplinkReader, Writer: downlinkWriter}, dialer)
+
+	packets := []string{"first packet", "second", "a third, longer packet that must stay whole"}
+	for _, packet := range packets {
+		b := buf.New()
+		common.Must2(b.WriteString(packet))
+		common.Must(uplinkWriter.WriteMultiBuffer(buf.MultiBuffer{b}))
+		var response buf.MultiBuffer
+		for len(response) == 0 {
+			mb, err := downlinkReader.ReadMultiBufferTimeout(5 * time.Second)
+			if err != nil {
+				t.Fatal("no response for ", packet, ": ", err)
+			}
+			response = append(response, mb...)
+		}
+		if len(response) != 1 || response.String() != packet {
+			t.Fatalf("packet %q came back as %d buffers: %q", packet, len(response), response.String())
+		}
+		buf.ReleaseMulti(response)
+		select {
+		case stamp := <-recorder.stamps:
+			if stamp != echo {
+				t.Error("packet ", packet, " stamped with ", stamp, ", expected ", echo)
+			}
+		default:
+			t.Error("packet ", packet, " reached the dispatcher without a stamp")
+		}
+	}
+	common.Must(uplinkWriter.Close())
+}
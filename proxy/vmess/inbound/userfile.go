@@ -0,0 +1,237 @@
+package inbound
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vmessocket/vmessocket/common/protocol"
+	"github.com/vmessocket/vmessocket/common/task"
+	"github.com/vmessocket/vmessocket/proxy/vmess"
+)
+
+const defaultUserFileInterval = 10 * time.Second
+
+type fileUser struct {
+	Email    string `json:"email"`
+	ID       string `json:"id"`
+	Level    uint32 `json:"level"`
+	Security string `json:"security"`
+}
+
+type userFileWatcher struct {
+	sync.Mutex
+	handler *Handler
+	path    string
+	modTime time.Time
+	size    int64
+	users   map[string]fileUser
+	task    *task.Periodic
+}
+
+func newUserFileWatcher(handler *Handler, config *UserFile) *userFileWatcher {
+	w := &userFileWatcher{
+		handler: handler,
+		path:    config.Path,
+		users:   make(map[string]fileUser),
+	}
+	interval := defaultUserFileInterval
+	if config.Interval > 0 {
+		interval = time.Duration(config.Interval) * time.Second
+	}
+	w.task = &task.Periodic{
+		Interval: interval,
+		Execute: func() error {
+			if err := w.reload(); err != nil {
+				newError("failed to reload user file ", w.path).Base(err).AtWarning().WriteToLog()
+			}
+			return nil
+		},
+	}
+	return w
+}
+
+func parseCSVUsers(data []byte) ([]fileUser, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	reader.Comment = '#'
+	var users []fileUser
+	for first := true; ; first = false {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if first && strings.EqualFold(record[0], "email") {
+			continue
+		}
+		line, _ := reader.FieldPos(0)
+		if len(record) < 2 {
+			return nil, newError("too few fields on line ", line)
+		}
+		u := fileUser{
+			Email: record[0],
+			ID:    record[1],
+		}
+		if len(record) > 2 && record[2] != "" {
+			level, err := strconv.ParseUint(record[2], 10, 32)
+			if err != nil {
+				return nil, newError("invalid level on line ", line).Base(err)
+			}
+			u.Level = uint32(level)
+		}
+		if len(record) > 3 {
+			u.Security = record[3]
+		}
+		users = append(users, u)
+	}
+	return users, nil
+}
+
+func parseSecurity(s string) (protocol.SecurityType, error) {
+	switch strings.ToLower(s) {
+	case "", "auto":
+		return protocol.SecurityType_AUTO, nil
+	case "aes-128-gcm":
+		return protocol.SecurityType_AES128_GCM, nil
+	case "chacha20-poly1305":
+		return protocol.SecurityType_CHACHA20_POLY1305, nil
+	case "none":
+		return protocol.SecurityType_NONE, nil
+	case "zero":
+		return protocol.SecurityType_ZERO, nil
+	default:
+		return protocol.SecurityType_UNKNOWN, newError("unknown security type: ", s)
+	}
+}
+
+func readUserFile(path string) (map[string]fileUser, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var list []fileUser
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		list, err = parseCSVUsers(data)
+	} else {
+		err = json.Unmarshal(data, &list)
+	}
+	if err != nil {
+		return nil, newError("failed to parse user file").Base(err)
+	}
+	users := make(map[string]fileUser, len(list))
+	for _, u := range list {
+		u.Email = strings.TrimSpace(u.Email)
+		if u.Email == "" {
+			return nil, newError("user ", u.ID, " has no email")
+		}
+		if _, err := u.memoryUser(); err != nil {
+			return nil, newError("invalid user ", u.Email).Base(err)
+		}
+		key := strings.ToLower(u.Email)
+		if _, found := users[key]; found {
+			return nil, newError("duplicated user ", u.Email)
+		}
+		users[key] = u
+	}
+	return users, nil
+}
+
+func (w *userFileWatcher) Close() error {
+	return w.task.Close()
+}
+
+func (u fileUser) memoryUser() (*protocol.MemoryUser, error) {
+	security, err := parseSecurity(u.Security)
+	if err != nil {
+		return nil, err
+	}
+	account, err := (&vmess.Account{
+		Id: u.ID,
+		SecuritySettings: &protocol.SecurityConfig{
+			Type: security,
+		},
+	}).AsAccount()
+	if err != nil {
+		return nil, err
+	}
+	return &protocol.MemoryUser{
+		Level:   u.Level,
+		Email:   u.Email,
+		Account: account,
+	}, nil
+}
+
+func (w *userFileWatcher) reload() error {
+	w.Lock()
+	defer w.Unlock()
+	info, err := os.Stat(w.path)
+	if err != nil {
+		return err
+	}
+	if info.ModTime().Equal(w.modTime) && info.Size() == w.size {
+		return nil
+	}
+	users, err := readUserFile(w.path)
+	if err != nil {
+		return err
+	}
+	for key, u := range users {
+		if _, found := w.users[key]; found {
+			continue
+		}
+		if w.handler.usersByEmail.Has(u.Email) {
+			return newError("user ", u.Email, " already exists outside the user file")
+		}
+	}
+	ctx := context.Background()
+	var added, removed int
+	for key, old := range w.users {
+		if u, found := users[key]; found && u == old {
+			continue
+		}
+		if err := w.handler.RemoveUser(ctx, old.Email); err != nil {
+			newError("failed to remove user ", old.Email).Base(err).AtWarning().WriteToLog()
+		}
+		delete(w.users, key)
+		removed++
+	}
+	for key, u := range users {
+		if _, found := w.users[key]; found {
+			continue
+		}
+		mUser, err := u.memoryUser()
+		if err != nil {
+			return newError("failed to build user ", u.Email).Base(err)
+		}
+		if err := w.handler.AddUser(ctx, mUser); err != nil {
+			return newError("failed to add user ", u.Email).Base(err)
+		}
+		w.users[key] = u
+		added++
+	}
+	w.modTime = info.ModTime()
+	w.size = info.Size()
+	if added > 0 || removed > 0 {
+		newError("user file ", w.path, " reloaded: ", added, " added, ", removed, " removed").AtInfo().WriteToLog()
+	}
+	return nil
+}
+
+func (w *userFileWatcher) Start() error {
+	if err := w.reload(); err != nil {
+		return newError("failed to load user file ", w.path).Base(err)
+	}
+	return w.task.Start()
+}
@@ -0,0 +1,118 @@
+package inbound
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vmessocket/vmessocket/common"
+	"github.com/vmessocket/vmessocket/common/protocol"
+	"github.com/vmessocket/vmessocket/proxy/vmess"
+)
+
+const (
+	aliceID = "b831381d-6324-4d53-ad4f-8cda48b30811"
+	bobID   = "c1e0b6e4-6f2c-4a4b-9d4a-0a2f64c1a6f0"
+	carolID = "5a3e4f1c-0b9d-4c1e-8f7a-2d6b9e0c4a11"
+)
+
+func newUserFileTestHandler(t *testing.T) *Handler {
+	t.Helper()
+	h := &Handler{
+		clients:      vmess.NewTimedUserValidator(protocol.DefaultIDHash),
+		usersByEmail: newUserByEmail(&DefaultConfig{}, false),
+	}
+	t.Cleanup(func() {
+		h.clients.Close()
+	})
+	return h
+}
+
+func writeUserFile(t *testing.T, path string, content string, modTime time.Time) {
+	t.Helper()
+	common.Must(os.WriteFile(path, []byte(content), 0o600))
+	common.Must(os.Chtimes(path, modTime, modTime))
+}
+
+func TestParseCSVUsers(t *testing.T) {
+	users, err := parseCSVUsers([]byte("Email,ID,Level,Security\n# carol is disabled\nalice@example.com, " + aliceID + ", 2, aes-128-gcm\nbob@example.com," + bobID + "\n"))
+	common.Must(err)
+	expected := []fileUser{
+		{Email: "alice@example.com", ID: aliceID, Level: 2, Security: "aes-128-gcm"},
+		{Email: "bob@example.com", ID: bobID},
+	}
+	if len(users) != len(expected) {
+		t.Fatal("unexpected users: ", users)
+	}
+	for i := range users {
+		if users[i] != expected[i] {
+			t.Error("unexpected user ", users[i])
+		}
+	}
+	for input, msg := range map[string]string{
+		"email,id\nalice@example.com\n":          "too few fields on line 2",
+		"alice@example.com," + aliceID + ",-1\n": "invalid level on line 1",
+	} {
+		if _, err := parseCSVUsers([]byte(input)); err == nil || !strings.Contains(err.Error(), msg) {
+			t.Error("expected ", msg, ", got ", err)
+		}
+	}
+}
+
+func TestReadUserFileRejectsDuplicates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "users.json")
+	writeUserFile(t, path, `[{"email":"alice@example.com","id":"`+aliceID+`"},{"email":" Alice@Example.com ","id":"`+bobID+`"}]`, time.Now())
+	if _, err := readUserFile(path); err == nil || !strings.Contains(err.Error(), "duplicated user") {
+		t.Fatal("expected duplicated user error, got ", err)
+	}
+}
+
+func TestUserFileReload(t *testing.T) {
+	h := newUserFileTestHandler(t)
+	common.Must(h.AddUser(context.Background(), &protocol.MemoryUser{
+		Email:   "static@example.com",
+		Account: common.Must2((&vmess.Account{Id: carolID}).AsAccount()).(*vmess.MemoryAccount),
+	}))
+	path := filepath.Join(t.TempDir(), "users.csv")
+	w := newUserFileWatcher(h, &UserFile{Path: path})
+	modTime := time.Now().Add(-time.Hour).Truncate(time.Second)
+
+	writeUserFile(t, path, "alice@example.com,"+aliceID+"\nbob@example.com,"+bobID+"\n", modTime)
+	common.Must(w.reload())
+	if !h.usersByEmail.Has("alice@example.com") || !h.usersByEmail.Has("bob@example.com") {
+		t.Fatal("users from the file were not added")
+	}
+
+	modTime = modTime.Add(time.Second)
+	writeUserFile(t, path, "alice@example.com,zzzzzzzz-6324-4d53-ad4f-8cda48b30811\n", modTime)
+	if err := w.reload(); err == nil {
+		t.Fatal("expected a parse error")
+	}
+	if !h.usersByEmail.Has("bob@example.com") {
+		t.Fatal("a failed reload removed users")
+	}
+	writeUserFile(t, path, "alice@example.com,"+aliceID+"\n", modTime)
+	common.Must(w.reload())
+	if h.usersByEmail.Has("bob@example.com") {
+		t.Fatal("file fixed without changing its size and time was not reloaded")
+	}
+
+	modTime = modTime.Add(time.Second)
+	writeUserFile(t, path, "alice@example.com,"+aliceID+"\nSTATIC@example.com,"+bobID+"\n", modTime)
+	if err := w.reload(); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Fatal("expected a conflict with the static user, got ", err)
+	}
+	if len(w.users) != 1 || !h.usersByEmail.Has("alice@example.com") {
+		t.Fatal("a conflicting reload changed the file users: ", w.users)
+	}
+
+	modTime = modTime.Add(time.Second)
+	writeUserFile(t, path, "", modTime)
+	common.Must(w.reload())
+	if h.usersByEmail.Has("alice@example.com") || !h.usersByEmail.Has("static@example.com") {
+		t.Fatal("emptying the file must remove only the file users")
+	}
+}
@@ -21,12 +21,169 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type AEADMode int32
+
+const (
+	AEADMode_AEAD_MODE_DEFAULT  AEADMode = 0
+	AEADMode_AEAD_MODE_FORCED   AEADMode = 1
+	AEADMode_AEAD_MODE_DISABLED AEADMode = 2
+)
+
+// Enum value maps for AEADMode.
+var (
+	AEADMode_name = map[int32]string{
+		0: "AEAD_MODE_DEFAULT",
+		1: "AEAD_MODE_FORCED",
+		2: "AEAD_MODE_DISABLED",
+	}
+	AEADMode_value = map[string]int32{
+		"AEAD_MODE_DEFAULT":  0,
+		"AEAD_MODE_FORCED":   1,
+		"AEAD_MODE_DISABLED": 2,
+	}
+)
+
+func (x AEADMode) Enum() *AEADMode {
+	p := new(AEADMode)
+	*p = x
+	return p
+}
+
+func (x AEADMode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (AEADMode) Descriptor() protoreflect.EnumDescriptor {
+	return file_proxy_vmess_outbound_config_proto_enumTypes[0].Descriptor()
+}
+
+func (AEADMode) Type() protoreflect.EnumType {
+	return &file_proxy_vmess_outbound_config_proto_enumTypes[0]
+}
+
+func (x AEADMode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use AEADMode.Descriptor instead.
+func (AEADMode) EnumDescriptor() ([]byte, []int) {
+	return file_proxy_vmess_outbound_config_proto_rawDescGZIP(), []int{0}
+}
+
+type PaddingMode int32
+
+const (
+	PaddingMode_PADDING_MODE_DEFAULT  PaddingMode = 0
+	PaddingMode_PADDING_MODE_ENABLED  PaddingMode = 1
+	PaddingMode_PADDING_MODE_DISABLED PaddingMode = 2
+)
+
+// Enum value maps for PaddingMode.
+var (
+	PaddingMode_name = map[int32]string{
+		0: "PADDING_MODE_DEFAULT",
+		1: "PADDING_MODE_ENABLED",
+		2: "PADDING_MODE_DISABLED",
+	}
+	PaddingMode_value = map[string]int32{
+		"PADDING_MODE_DEFAULT":  0,
+		"PADDING_MODE_ENABLED":  1,
+		"PADDING_MODE_DISABLED": 2,
+	}
+)
+
+func (x PaddingMode) Enum() *PaddingMode {
+	p := new(PaddingMode)
+	*p = x
+	return p
+}
+
+func (x PaddingMode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PaddingMode) Descriptor() protoreflect.EnumDescriptor {
+	return file_proxy_vmess_outbound_config_proto_enumTypes[1].Descriptor()
+}
+
+func (PaddingMode) Type() protoreflect.EnumType {
+	return &file_proxy_vmess_outbound_config_proto_enumTypes[1]
+}
+
+func (x PaddingMode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PaddingMode.Descriptor instead.
+func (PaddingMode) EnumDescriptor() ([]byte, []int) {
+	return file_proxy_vmess_outbound_config_proto_rawDescGZIP(), []int{1}
+}
+
+type ServerStrategy int32
+
+const (
+	ServerStrategy_ROUND_ROBIN    ServerStrategy = 0
+	ServerStrategy_RANDOM         ServerStrategy = 1
+	ServerStrategy_LEAST_FAILURES ServerStrategy = 2
+	ServerStrategy_LOWEST_RTT     ServerStrategy = 3
+)
+
+// Enum value maps for ServerStrategy.
+var (
+	ServerStrategy_name = map[int32]string{
+		0: "ROUND_ROBIN",
+		1: "RANDOM",
+		2: "LEAST_FAILURES",
+		3: "LOWEST_RTT",
+	}
+	ServerStrategy_value = map[string]int32{
+		"ROUND_ROBIN":    0,
+		"RANDOM":         1,
+		"LEAST_FAILURES": 2,
+		"LOWEST_RTT":     3,
+	}
+)
+
+func (x ServerStrategy) Enum() *ServerStrategy {
+	p := new(ServerStrategy)
+	*p = x
+	return p
+}
+
+func (x ServerStrategy) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ServerStrategy) Descriptor() protoreflect.EnumDescriptor {
+	return file_proxy_vmess_outbound_config_proto_enumTypes[2].Descriptor()
+}
+
+func (ServerStrategy) Type() protoreflect.EnumType {
+	return &file_proxy_vmess_outbound_config_proto_enumTypes[2]
+}
+
+func (x ServerStrategy) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ServerStrategy.Descriptor instead.
+func (ServerStrategy) EnumDescriptor() ([]byte, []int) {
+	return file_proxy_vmess_outbound_config_proto_rawDescGZIP(), []int{2}
+}
+
 type Config struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Receiver []*protocol.ServerEndpoint `protobuf:"bytes,1,rep,name=Receiver,proto3" json:"Receiver,omitempty"`
+	Receiver            []*protocol.ServerEndpoint `protobuf:"bytes,1,rep,name=Receiver,proto3" json:"Receiver,omitempty"`
+	AeadMode            AEADMode                   `protobuf:"varint,2,opt,name=aead_mode,json=aeadMode,proto3,enum=vmessocket.core.proxy.vmess.outbound.AEADMode" json:"aead_mode,omitempty"`
+	PaddingMode         PaddingMode                `protobuf:"varint,3,opt,name=padding_mode,json=paddingMode,proto3,enum=vmessocket.core.proxy.vmess.outbound.PaddingMode" json:"padding_mode,omitempty"`
+	AuthenticatedLength bool                       `protobuf:"varint,4,opt,name=authenticated_length,json=authenticatedLength,proto3" json:"authenticated_length,omitempty"`
+	NoTerminationSignal bool                       `protobuf:"varint,5,opt,name=no_termination_signal,json=noTerminationSignal,proto3" json:"no_termination_signal,omitempty"`
+	Strategy            ServerStrategy             `protobuf:"varint,6,opt,name=strategy,proto3,enum=vmessocket.core.proxy.vmess.outbound.ServerStrategy" json:"strategy,omitempty"`
+	MaxFailures         uint32                     `protobuf:"varint,7,opt,name=max_failures,json=maxFailures,proto3" json:"max_failures,omitempty"`
+	EjectDuration       uint32                     `protobuf:"varint,8,opt,name=eject_duration,json=ejectDuration,proto3" json:"eject_duration,omitempty"`
 }
 
 func (x *Config) Reset() {
@@ -68,6 +225,55 @@ func (x *Config) GetReceiver() []*protocol.ServerEndpoint {
 	return nil
 }
 
+func (x *Config) GetAeadMode() AEADMode {
+	if x != nil {
+		return x.AeadMode
+	}
+	return AEADMode_AEAD_MODE_DEFAULT
+}
+
+func (x *Config) GetPaddingMode() PaddingMode {
+	if x != nil {
+		return x.PaddingMode
+	}
+	return PaddingMode_PADDING_MODE_DEFAULT
+}
+
+func (x *Config) GetAuthenticatedLength() bool {
+	if x != nil {
+		return x.AuthenticatedLength
+	}
+	return false
+}
+
+func (x *Config) GetNoTerminationSignal() bool {
+	if x != nil {
+		return x.NoTerminationSignal
+	}
+	return false
+}
+
+func (x *Config) GetStrategy() ServerStrategy {
+	if x != nil {
+		return x.Strategy
+	}
+	return ServerStrategy_ROUND_ROBIN
+}
+
+func (x *Config) GetMaxFailures() uint32 {
+	if x != nil {
+		return x.MaxFailures
+	}
+	return 0
+}
+
+func (x *Config) GetEjectDuration() uint32 {
+	if x != nil {
+		return x.EjectDuration
+	}
+	return 0
+}
+
 var File_proxy_vmess_outbound_config_proto protoreflect.FileDescriptor
 
 var file_proxy_vmess_outbound_config_proto_rawDesc = []byte{
@@ -77,22 +283,65 @@ var file_proxy_vmess_outbound_config_proto_rawDesc = []byte{
 	0x63, 0x6f, 0x72, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x78, 0x79, 0x2e, 0x76, 0x6d, 0x65, 0x73, 0x73,
 	0x2e, 0x6f, 0x75, 0x74, 0x62, 0x6f, 0x75, 0x6e, 0x64, 0x1a, 0x21, 0x63, 0x6f, 0x6d, 0x6d, 0x6f,
 	0x6e, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x2f, 0x73, 0x65, 0x72, 0x76, 0x65,
-	0x72, 0x5f, 0x73, 0x70, 0x65, 0x63, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x55, 0x0a, 0x06,
-	0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x4b, 0x0a, 0x08, 0x52, 0x65, 0x63, 0x65, 0x69, 0x76,
-	0x65, 0x72, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2f, 0x2e, 0x76, 0x6d, 0x65, 0x73, 0x73,
-	0x6f, 0x63, 0x6b, 0x65, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x6f,
-	0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x65,
-	0x72, 0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x52, 0x08, 0x52, 0x65, 0x63, 0x65, 0x69,
-	0x76, 0x65, 0x72, 0x42, 0x8a, 0x01, 0x0a, 0x28, 0x63, 0x6f, 0x6d, 0x2e, 0x76, 0x6d, 0x65, 0x73,
-	0x73, 0x6f, 0x63, 0x6b, 0x65, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x78,
-	0x79, 0x2e, 0x76, 0x6d, 0x65, 0x73, 0x73, 0x2e, 0x6f, 0x75, 0x74, 0x62, 0x6f, 0x75, 0x6e, 0x64,
-	0x50, 0x01, 0x5a, 0x35, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x76,
-	0x6d, 0x65, 0x73, 0x73, 0x6f, 0x63, 0x6b, 0x65, 0x74, 0x2f, 0x76, 0x6d, 0x65, 0x73, 0x73, 0x6f,
-	0x63, 0x6b, 0x65, 0x74, 0x2f, 0x70, 0x72, 0x6f, 0x78, 0x79, 0x2f, 0x76, 0x6d, 0x65, 0x73, 0x73,
-	0x2f, 0x6f, 0x75, 0x74, 0x62, 0x6f, 0x75, 0x6e, 0x64, 0xaa, 0x02, 0x24, 0x76, 0x6d, 0x65, 0x73,
-	0x73, 0x6f, 0x63, 0x6b, 0x65, 0x74, 0x2e, 0x43, 0x6f, 0x72, 0x65, 0x2e, 0x50, 0x72, 0x6f, 0x78,
-	0x79, 0x2e, 0x56, 0x6d, 0x65, 0x73, 0x73, 0x2e, 0x4f, 0x75, 0x74, 0x62, 0x6f, 0x75, 0x6e, 0x64,
-	0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x72, 0x5f, 0x73, 0x70, 0x65, 0x63, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xfb, 0x03, 0x0a,
+	0x06, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x4b, 0x0a, 0x08, 0x52, 0x65, 0x63, 0x65, 0x69,
+	0x76, 0x65, 0x72, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2f, 0x2e, 0x76, 0x6d, 0x65, 0x73,
+	0x73, 0x6f, 0x63, 0x6b, 0x65, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x63, 0x6f, 0x6d, 0x6d,
+	0x6f, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x2e, 0x53, 0x65, 0x72, 0x76,
+	0x65, 0x72, 0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x52, 0x08, 0x52, 0x65, 0x63, 0x65,
+	0x69, 0x76, 0x65, 0x72, 0x12, 0x4b, 0x0a, 0x09, 0x61, 0x65, 0x61, 0x64, 0x5f, 0x6d, 0x6f, 0x64,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x2e, 0x2e, 0x76, 0x6d, 0x65, 0x73, 0x73, 0x6f,
+	0x63, 0x6b, 0x65, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x78, 0x79, 0x2e,
+	0x76, 0x6d, 0x65, 0x73, 0x73, 0x2e, 0x6f, 0x75, 0x74, 0x62, 0x6f, 0x75, 0x6e, 0x64, 0x2e, 0x41,
+	0x45, 0x41, 0x44, 0x4d, 0x6f, 0x64, 0x65, 0x52, 0x08, 0x61, 0x65, 0x61, 0x64, 0x4d, 0x6f, 0x64,
+	0x65, 0x12, 0x54, 0x0a, 0x0c, 0x70, 0x61, 0x64, 0x64, 0x69, 0x6e, 0x67, 0x5f, 0x6d, 0x6f, 0x64,
+	0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x31, 0x2e, 0x76, 0x6d, 0x65, 0x73, 0x73, 0x6f,
+	0x63, 0x6b, 0x65, 0x74, 0x2e, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x78, 0x79, 0x2e,
+	0x76, 0x6d, 0x65, 0x73, 0x73, 0x2e, 0x6f, 0x75, 0x74, 0x62, 0x6f, 0x75, 0x6e, 0x64, 0x2e, 0x50,
+	0x61, 0x64, 0x64, 0x69, 0x6e, 0x67, 0x4d, 0x6f, 0x64, 0x65, 0x52, 0x0b, 0x70, 0x61, 0x64, 0x64,
+	0x69, 0x6e, 0x67, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x31, 0x0a, 0x14, 0x61, 0x75, 0x74, 0x68, 0x65,
+	0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x6c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x13, 0x61, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63,
+	0x61, 0x74, 0x65, 0x64, 0x4c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x12, 0x32, 0x0a, 0x15, 0x6e, 0x6f,
+	0x5f, 0x74, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x73, 0x69, 0x67,
+	0x6e, 0x61, 0x6c, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x13, 0x6e, 0x6f, 0x54, 0x65, 0x72,
+	0x6d, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x6c, 0x12, 0x50,
+	0x0a, 0x08, 0x73, 0x74, 0x72, 0x61, 0x74, 0x65, 0x67, 0x79, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x34, 0x2e, 0x76, 0x6d, 0x65, 0x73, 0x73, 0x6f, 0x63, 0x6b, 0x65, 0x74, 0x2e, 0x63, 0x6f,
+	0x72, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x78, 0x79, 0x2e, 0x76, 0x6d, 0x65, 0x73, 0x73, 0x2e, 0x6f,
+	0x75, 0x74, 0x62, 0x6f, 0x75, 0x6e, 0x64, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x53, 0x74,
+	0x72, 0x61, 0x74, 0x65, 0x67, 0x79, 0x52, 0x08, 0x73, 0x74, 0x72, 0x61, 0x74, 0x65, 0x67, 0x79,
+	0x12, 0x21, 0x0a, 0x0c, 0x6d, 0x61, 0x78, 0x5f, 0x66, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x73,
+	0x18, 0x07, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0b, 0x6d, 0x61, 0x78, 0x46, 0x61, 0x69, 0x6c, 0x75,
+	0x72, 0x65, 0x73, 0x12, 0x25, 0x0a, 0x0e, 0x65, 0x6a, 0x65, 0x63, 0x74, 0x5f, 0x64, 0x75, 0x72,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0d, 0x65, 0x6a, 0x65,
+	0x63, 0x74, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2a, 0x4f, 0x0a, 0x08, 0x41, 0x45,
+	0x41, 0x44, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x15, 0x0a, 0x11, 0x41, 0x45, 0x41, 0x44, 0x5f, 0x4d,
+	0x4f, 0x44, 0x45, 0x5f, 0x44, 0x45, 0x46, 0x41, 0x55, 0x4c, 0x54, 0x10, 0x00, 0x12, 0x14, 0x0a,
+	0x10, 0x41, 0x45, 0x41, 0x44, 0x5f, 0x4d, 0x4f, 0x44, 0x45, 0x5f, 0x46, 0x4f, 0x52, 0x43, 0x45,
+	0x44, 0x10, 0x01, 0x12, 0x16, 0x0a, 0x12, 0x41, 0x45, 0x41, 0x44, 0x5f, 0x4d, 0x4f, 0x44, 0x45,
+	0x5f, 0x44, 0x49, 0x53, 0x41, 0x42, 0x4c, 0x45, 0x44, 0x10, 0x02, 0x2a, 0x5c, 0x0a, 0x0b, 0x50,
+	0x61, 0x64, 0x64, 0x69, 0x6e, 0x67, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x18, 0x0a, 0x14, 0x50, 0x41,
+	0x44, 0x44, 0x49, 0x4e, 0x47, 0x5f, 0x4d, 0x4f, 0x44, 0x45, 0x5f, 0x44, 0x45, 0x46, 0x41, 0x55,
+	0x4c, 0x54, 0x10, 0x00, 0x12, 0x18, 0x0a, 0x14, 0x50, 0x41, 0x44, 0x44, 0x49, 0x4e, 0x47, 0x5f,
+	0x4d, 0x4f, 0x44, 0x45, 0x5f, 0x45, 0x4e, 0x41, 0x42, 0x4c, 0x45, 0x44, 0x10, 0x01, 0x12, 0x19,
+	0x0a, 0x15, 0x50, 0x41, 0x44, 0x44, 0x49, 0x4e, 0x47, 0x5f, 0x4d, 0x4f, 0x44, 0x45, 0x5f, 0x44,
+	0x49, 0x53, 0x41, 0x42, 0x4c, 0x45, 0x44, 0x10, 0x02, 0x2a, 0x51, 0x0a, 0x0e, 0x53, 0x65, 0x72,
+	0x76, 0x65, 0x72, 0x53, 0x74, 0x72, 0x61, 0x74, 0x65, 0x67, 0x79, 0x12, 0x0f, 0x0a, 0x0b, 0x52,
+	0x4f, 0x55, 0x4e, 0x44, 0x5f, 0x52, 0x4f, 0x42, 0x49, 0x4e, 0x10, 0x00, 0x12, 0x0a, 0x0a, 0x06,
+	0x52, 0x41, 0x4e, 0x44, 0x4f, 0x4d, 0x10, 0x01, 0x12, 0x12, 0x0a, 0x0e, 0x4c, 0x45, 0x41, 0x53,
+	0x54, 0x5f, 0x46, 0x41, 0x49, 0x4c, 0x55, 0x52, 0x45, 0x53, 0x10, 0x02, 0x12, 0x0e, 0x0a, 0x0a,
+	0x4c, 0x4f, 0x57, 0x45, 0x53, 0x54, 0x5f, 0x52, 0x54, 0x54, 0x10, 0x03, 0x42, 0x8a, 0x01, 0x0a,
+	0x28, 0x63, 0x6f, 0x6d, 0x2e, 0x76, 0x6d, 0x65, 0x73, 0x73, 0x6f, 0x63, 0x6b, 0x65, 0x74, 0x2e,
+	0x63, 0x6f, 0x72, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x78, 0x79, 0x2e, 0x76, 0x6d, 0x65, 0x73, 0x73,
+	0x2e, 0x6f, 0x75, 0x74, 0x62, 0x6f, 0x75, 0x6e, 0x64, 0x50, 0x01, 0x5a, 0x35, 0x67, 0x69, 0x74,
+	0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x76, 0x6d, 0x65, 0x73, 0x73, 0x6f, 0x63, 0x6b,
+	0x65, 0x74, 0x2f, 0x76, 0x6d, 0x65, 0x73, 0x73, 0x6f, 0x63, 0x6b, 0x65, 0x74, 0x2f, 0x70, 0x72,
+	0x6f, 0x78, 0x79, 0x2f, 0x76, 0x6d, 0x65, 0x73, 0x73, 0x2f, 0x6f, 0x75, 0x74, 0x62, 0x6f, 0x75,
+	0x6e, 0x64, 0xaa, 0x02, 0x24, 0x76, 0x6d, 0x65, 0x73, 0x73, 0x6f, 0x63, 0x6b, 0x65, 0x74, 0x2e,
+	0x43, 0x6f, 0x72, 0x65, 0x2e, 0x50, 0x72, 0x6f, 0x78, 0x79, 0x2e, 0x56, 0x6d, 0x65, 0x73, 0x73,
+	0x2e, 0x4f, 0x75, 0x74, 0x62, 0x6f, 0x75, 0x6e, 0x64, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x33,
 }
 
 var (
@@ -107,18 +356,25 @@ func file_proxy_vmess_outbound_config_proto_rawDescGZIP() []byte {
 	return file_proxy_vmess_outbound_config_proto_rawDescData
 }
 
+var file_proxy_vmess_outbound_config_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
 var file_proxy_vmess_outbound_config_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
 var file_proxy_vmess_outbound_config_proto_goTypes = []interface{}{
-	(*Config)(nil),                  // 0: vmessocket.core.proxy.vmess.outbound.Config
-	(*protocol.ServerEndpoint)(nil), // 1: vmessocket.core.common.protocol.ServerEndpoint
+	(AEADMode)(0),                   // 0: vmessocket.core.proxy.vmess.outbound.AEADMode
+	(PaddingMode)(0),                // 1: vmessocket.core.proxy.vmess.outbound.PaddingMode
+	(ServerStrategy)(0),             // 2: vmessocket.core.proxy.vmess.outbound.ServerStrategy
+	(*Config)(nil),                  // 3: vmessocket.core.proxy.vmess.outbound.Config
+	(*protocol.ServerEndpoint)(nil), // 4: vmessocket.core.common.protocol.ServerEndpoint
 }
 var file_proxy_vmess_outbound_config_proto_depIdxs = []int32{
-	1, // 0: vmessocket.core.proxy.vmess.outbound.Config.Receiver:type_name -> vmessocket.core.common.protocol.ServerEndpoint
-	1, // [1:1] is the sub-list for method output_type
-	1, // [1:1] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	4, // 0: vmessocket.core.proxy.vmess.outbound.Config.Receiver:type_name -> vmessocket.core.common.protocol.ServerEndpoint
+	0, // 1: vmessocket.core.proxy.vmess.outbound.Config.aead_mode:type_name -> vmessocket.core.proxy.vmess.outbound.AEADMode
+	1, // 2: vmessocket.core.proxy.vmess.outbound.Config.padding_mode:type_name -> vmessocket.core.proxy.vmess.outbound.PaddingMode
+	2, // 3: vmessocket.core.proxy.vmess.outbound.Config.strategy:type_name -> vmessocket.core.proxy.vmess.outbound.ServerStrategy
+	4, // [4:4] is the sub-list for method output_type
+	4, // [4:4] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
 }
 
 func init() { file_proxy_vmess_outbound_config_proto_init() }
@@ -145,13 +401,14 @@ func file_proxy_vmess_outbound_config_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_proxy_vmess_outbound_config_proto_rawDesc,
-			NumEnums:      0,
+			NumEnums:      3,
 			NumMessages:   1,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
 		GoTypes:           file_proxy_vmess_outbound_config_proto_goTypes,
 		DependencyIndexes: file_proxy_vmess_outbound_config_proto_depIdxs,
+		EnumInfos:         file_proxy_vmess_outbound_config_proto_enumTypes,
 		MessageInfos:      file_proxy_vmess_outbound_config_proto_msgTypes,
 	}.Build()
 	File_proxy_vmess_outbound_config_proto = out.File
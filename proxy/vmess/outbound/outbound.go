@@ -10,7 +10,6 @@ import (
 	"github.com/vmessocket/vmessocket/common"
 	"github.com/vmessocket/vmessocket/common/buf"
 	"github.com/vmessocket/vmessocket/common/net"
-	"github.com/vmessocket/vmessocket/common/platform"
 	"github.com/vmessocket/vmessocket/common/protocol"
 	"github.com/vmessocket/vmessocket/common/retry"
 	"github.com/vmessocket/vmessocket/common/session"
@@ -22,14 +21,13 @@ import (
 	"github.com/vmessocket/vmessocket/transport/internet"
 )
 
-var (
-	aeadDisabled  = false
-	enablePadding = false
-)
-
 type Handler struct {
-	serverList   *protocol.ServerList
-	serverPicker protocol.ServerPicker
+	serverList          *protocol.ServerList
+	serverPicker        protocol.ServerPicker
+	aeadMode            AEADMode
+	paddingMode         PaddingMode
+	authenticatedLength bool
+	noTerminationSignal bool
 }
 
 func New(ctx context.Context, config *Config) (*Handler, error) {
@@ -41,24 +39,45 @@ func New(ctx context.Context, config *Config) (*Handler, error) {
 		}
 		serverList.AddServer(s)
 	}
+	ejectDuration := time.Duration(config.EjectDuration) * time.Second
+	var serverPicker protocol.ServerPicker
+	switch config.Strategy {
+	case ServerStrategy_RANDOM:
+		serverPicker = protocol.NewRandomServerPicker(serverList)
+	case ServerStrategy_LEAST_FAILURES:
+		serverPicker = protocol.NewLeastFailuresServerPicker(serverList, config.MaxFailures, ejectDuration)
+	case ServerStrategy_LOWEST_RTT:
+		serverPicker = protocol.NewLowestRTTServerPicker(serverList, config.MaxFailures, ejectDuration)
+	default:
+		serverPicker = protocol.NewRoundRobinServerPicker(serverList)
+	}
 	handler := &Handler{
-		serverList:   serverList,
-		serverPicker: protocol.NewRoundRobinServerPicker(serverList),
+		serverList:          serverList,
+		serverPicker:        serverPicker,
+		aeadMode:            config.AeadMode,
+		paddingMode:         config.PaddingMode,
+		authenticatedLength: config.AuthenticatedLength,
+		noTerminationSignal: config.NoTerminationSignal,
 	}
 	return handler, nil
 }
 
-func shouldEnablePadding(s protocol.SecurityType) bool {
-	return enablePadding || s == protocol.SecurityType_AES128_GCM || s == protocol.SecurityType_CHACHA20_POLY1305 || s == protocol.SecurityType_AUTO
-}
-
 func (h *Handler) Process(ctx context.Context, link *transport.Link, dialer internet.Dialer) error {
 	var rec *protocol.ServerSpec
 	var conn internet.Connection
+	var start time.Time
+	reporter, _ := h.serverPicker.(protocol.ServerReporter)
 	err := retry.ExponentialBackoff(5, 200).On(func() error {
 		rec = h.serverPicker.PickServer()
+		if rec == nil {
+			return newError("no server available")
+		}
+		start = time.Now()
 		rawConn, err := dialer.Dial(ctx, rec.Destination())
 		if err != nil {
+			if reporter != nil && ctx.Err() == nil {
+				reporter.ReportServer(rec, 0, err)
+			}
 			return err
 		}
 		conn = rawConn
@@ -78,6 +97,9 @@ func (h *Handler) Process(ctx context.Context, link *transport.Link, dialer inte
 	if target.Network == net.Network_UDP {
 		command = protocol.RequestCommandUDP
 	}
+	if target.Address.Family().IsDomain() && target.Address.Domain() == "v1.mux.cool" {
+		command = protocol.RequestCommandMux
+	}
 	user := rec.PickUser()
 	request := &protocol.RequestHeader{
 		Version: encoding.Version,
@@ -92,7 +114,7 @@ func (h *Handler) Process(ctx context.Context, link *transport.Link, dialer inte
 	if request.Security == protocol.SecurityType_AES128_GCM || request.Security == protocol.SecurityType_NONE || request.Security == protocol.SecurityType_CHACHA20_POLY1305 {
 		request.Option.Set(protocol.RequestOptionChunkMasking)
 	}
-	if shouldEnablePadding(request.Security) && request.Option.Has(protocol.RequestOptionChunkMasking) {
+	if h.shouldEnablePadding(request.Security) && request.Option.Has(protocol.RequestOptionChunkMasking) {
 		request.Option.Set(protocol.RequestOptionGlobalPadding)
 	}
 	if request.Security == protocol.SecurityType_ZERO {
@@ -100,15 +122,12 @@ func (h *Handler) Process(ctx context.Context, link *transport.Link, dialer inte
 		request.Option.Clear(protocol.RequestOptionChunkStream)
 		request.Option.Clear(protocol.RequestOptionChunkMasking)
 	}
-	if account.AuthenticatedLengthExperiment {
+	if account.AuthenticatedLengthExperiment || h.authenticatedLength {
 		request.Option.Set(protocol.RequestOptionAuthenticatedLength)
 	}
 	input := link.Reader
 	output := link.Writer
-	isAEAD := false
-	if !aeadDisabled && len(account.AlterIDs) == 0 {
-		isAEAD = true
-	}
+	isAEAD := h.useAEAD(account)
 	hashkdf := hmac.New(sha256.New, []byte("VMessBF"))
 	hashkdf.Write(account.ID.Bytes())
 	behaviorSeed := crc64.Checksum(hashkdf.Sum(nil), crc64.MakeTable(crc64.ISO))
@@ -130,7 +149,7 @@ func (h *Handler) Process(ctx context.Context, link *transport.Link, dialer inte
 		if err := buf.Copy(input, bodyWriter, buf.UpdateActivity(timer)); err != nil {
 			return err
 		}
-		if request.Option.Has(protocol.RequestOptionChunkStream) && !account.NoTerminationSignal {
+		if request.Option.Has(protocol.RequestOptionChunkStream) && !account.NoTerminationSignal && !h.noTerminationSignal {
 			if err := bodyWriter.WriteMultiBuffer(buf.MultiBuffer{}); err != nil {
 				return err
 			}
@@ -140,6 +159,9 @@ func (h *Handler) Process(ctx context.Context, link *transport.Link, dialer inte
 	responseDone := func() error {
 		reader := &buf.BufferedReader{Reader: buf.NewReader(conn)}
 		header, err := session.DecodeResponseHeader(reader)
+		if reporter != nil && ctx.Err() == nil {
+			reporter.ReportServer(rec, time.Since(start), err)
+		}
 		if err != nil {
 			return newError("failed to read header").Base(err)
 		}
@@ -154,17 +176,30 @@ func (h *Handler) Process(ctx context.Context, link *transport.Link, dialer inte
 	return nil
 }
 
+func (h *Handler) shouldEnablePadding(s protocol.SecurityType) bool {
+	switch h.paddingMode {
+	case PaddingMode_PADDING_MODE_ENABLED:
+		return true
+	case PaddingMode_PADDING_MODE_DISABLED:
+		return false
+	default:
+		return s == protocol.SecurityType_AES128_GCM || s == protocol.SecurityType_CHACHA20_POLY1305 || s == protocol.SecurityType_AUTO
+	}
+}
+
+func (h *Handler) useAEAD(account *vmess.MemoryAccount) bool {
+	switch h.aeadMode {
+	case AEADMode_AEAD_MODE_FORCED:
+		return true
+	case AEADMode_AEAD_MODE_DISABLED:
+		return false
+	default:
+		return len(account.AlterIDs) == 0
+	}
+}
+
 func init() {
 	common.Must(common.RegisterConfig((*Config)(nil), func(ctx context.Context, config interface{}) (interface{}, error) {
 		return New(ctx, config.(*Config))
 	}))
-	const defaultFlagValue = "NOT_DEFINED_AT_ALL"
-	paddingValue := platform.NewEnvFlag("vmessocket.vmess.padding").GetValue(func() string { return defaultFlagValue })
-	if paddingValue != defaultFlagValue {
-		enablePadding = true
-	}
-	isAeadDisabled := platform.NewEnvFlag("vmessocket.vmess.aead.disabled").GetValue(func() string { return defaultFlagValue })
-	if isAeadDisabled == "true" {
-		aeadDisabled = true
-	}
 }
@@ -0,0 +1,30 @@
+package outbound
+
+import (
+	"testing"
+
+	"github.com/vmessocket/vmessocket/common/protocol"
+)
+
+func TestShouldEnablePadding(t *testing.T) {
+	testCases := []struct {
+		mode     PaddingMode
+		security protocol.SecurityType
+		expected bool
+	}{
+		{mode: PaddingMode_PADDING_MODE_DEFAULT, security: protocol.SecurityType_AES128_GCM, expected: true},
+		{mode: PaddingMode_PADDING_MODE_DEFAULT, security: protocol.SecurityType_AUTO, expected: true},
+		{mode: PaddingMode_PADDING_MODE_DEFAULT, security: protocol.SecurityType_NONE, expected: false},
+		{mode: PaddingMode_PADDING_MODE_ENABLED, security: protocol.SecurityType_NONE, expected: true},
+		{mode: PaddingMode_PADDING_MODE_DISABLED, security: protocol.SecurityType_AES128_GCM, expected: false},
+		{mode: PaddingMode_PADDING_MODE_DISABLED, security: protocol.SecurityType_CHACHA20_POLY1305, expected: false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.mode.String()+"/"+tc.security.String(), func(t *testing.T) {
+			h := &Handler{paddingMode: tc.mode}
+			if actual := h.shouldEnablePadding(tc.security); actual != tc.expected {
+				t.Fatalf("expected %v, got %v", tc.expected, actual)
+			}
+		})
+	}
+}
@@ -18,8 +18,9 @@ import (
 )
 
 const (
-	updateInterval   = 10 * time.Second
-	cacheDurationSec = 120
+	updateInterval     = 10 * time.Second
+	cacheDurationSec   = 120
+	legacyIdleDuration = 10 * time.Minute
 )
 var (
 	ErrNotFound = newError("Not Found")
@@ -43,6 +44,10 @@ type TimedUserValidator struct {
 	behaviorFused      bool
 	aeadDecoderHolder *aead.AuthIDDecoderHolder
 	legacyWarnShown    bool
+	legacyDisabled     uint32
+	legacyActive       uint32
+	legacyLastUsed     int64
+	legacyBuild        sync.Mutex
 }
 
 type user struct {
@@ -78,7 +83,9 @@ func (v *TimedUserValidator) Add(u *protocol.MemoryUser) error {
 		lastSec: protocol.Timestamp(nowSec - cacheDurationSec),
 	}
 	v.users = append(v.users, uu)
-	v.generateNewHashes(protocol.Timestamp(nowSec), uu)
+	if atomic.LoadUint32(&v.legacyActive) == 1 {
+		v.generateNewHashes(protocol.Timestamp(nowSec), uu)
+	}
 	account := uu.user.Account.(*MemoryAccount)
 	if !v.behaviorFused {
 		hashkdf := hmac.New(sha256.New, []byte("VMESSBSKDF"))
@@ -110,21 +117,16 @@ func (v *TimedUserValidator) Close() error {
 	return v.task.Close()
 }
 
-func (v *TimedUserValidator) generateNewHashes(nowSec protocol.Timestamp, user *user) {
+func (v *TimedUserValidator) generateHashes(hashes map[[16]byte]indexTimePair, user *user, beginSec, endSec protocol.Timestamp) {
 	var hashValue [16]byte
-	genEndSec := nowSec + cacheDurationSec
 	genHashForID := func(id *protocol.ID) {
 		idHash := v.hasher(id.Bytes())
-		genBeginSec := user.lastSec
-		if genBeginSec < nowSec-cacheDurationSec {
-			genBeginSec = nowSec - cacheDurationSec
-		}
-		for ts := genBeginSec; ts <= genEndSec; ts++ {
+		for ts := beginSec; ts <= endSec; ts++ {
 			common.Must2(serial.WriteUint64(idHash, uint64(ts)))
 			idHash.Sum(hashValue[:0])
 			idHash.Reset()
 
-			v.userHash[hashValue] = indexTimePair{
+			hashes[hashValue] = indexTimePair{
 				user:        user,
 				timeInc:     uint32(ts - v.baseTime),
 				taintedFuse: new(uint32),
@@ -136,10 +138,74 @@ func (v *TimedUserValidator) generateNewHashes(nowSec protocol.Timestamp, user *
 	for _, id := range account.AlterIDs {
 		genHashForID(id)
 	}
+}
+
+func (v *TimedUserValidator) generateNewHashes(nowSec protocol.Timestamp, user *user) {
+	genBeginSec := user.lastSec
+	if genBeginSec < nowSec-cacheDurationSec {
+		genBeginSec = nowSec - cacheDurationSec
+	}
+	genEndSec := nowSec + cacheDurationSec
+	v.generateHashes(v.userHash, user, genBeginSec, genEndSec)
 	user.lastSec = genEndSec
 }
 
+func (v *TimedUserValidator) DisableLegacy() {
+	v.Lock()
+	defer v.Unlock()
+	atomic.StoreUint32(&v.legacyDisabled, 1)
+	atomic.StoreUint32(&v.legacyActive, 0)
+	v.userHash = make(map[[16]byte]indexTimePair)
+}
+
+func (v *TimedUserValidator) ensureLegacyHashes() bool {
+	if atomic.LoadUint32(&v.legacyDisabled) == 1 {
+		return false
+	}
+	atomic.StoreInt64(&v.legacyLastUsed, time.Now().Unix())
+	if atomic.LoadUint32(&v.legacyActive) == 1 {
+		return true
+	}
+	v.legacyBuild.Lock()
+	defer v.legacyBuild.Unlock()
+	if atomic.LoadUint32(&v.legacyActive) == 1 {
+		return true
+	}
+	v.RLock()
+	users := make([]*user, len(v.users))
+	copy(users, v.users)
+	v.RUnlock()
+	nowSec := protocol.Timestamp(time.Now().Unix())
+	hashes := make(map[[16]byte]indexTimePair, 1024)
+	for _, user := range users {
+		v.generateHashes(hashes, user, nowSec-cacheDurationSec, nowSec+cacheDurationSec)
+	}
+	v.Lock()
+	defer v.Unlock()
+	if atomic.LoadUint32(&v.legacyDisabled) == 1 {
+		return false
+	}
+	built := make(map[*user]bool, len(users))
+	for _, user := range users {
+		built[user] = true
+	}
+	v.userHash = hashes
+	for _, user := range v.users {
+		if built[user] {
+			user.lastSec = nowSec + cacheDurationSec
+			continue
+		}
+		user.lastSec = nowSec - cacheDurationSec
+		v.generateNewHashes(nowSec, user)
+	}
+	atomic.StoreUint32(&v.legacyActive, 1)
+	return true
+}
+
 func (v *TimedUserValidator) Get(userHash []byte) (*protocol.MemoryUser, protocol.Timestamp, bool, error) {
+	if !v.ensureLegacyHashes() {
+		return nil, 0, false, ErrNotFound
+	}
 	v.RLock()
 	defer v.RUnlock()
 	v.behaviorFused = true
@@ -223,6 +289,14 @@ func (v *TimedUserValidator) updateUserHash() {
 	nowSec := protocol.Timestamp(now.Unix())
 	v.Lock()
 	defer v.Unlock()
+	if atomic.LoadUint32(&v.legacyActive) == 0 {
+		return
+	}
+	if now.Unix()-atomic.LoadInt64(&v.legacyLastUsed) > int64(legacyIdleDuration/time.Second) {
+		atomic.StoreUint32(&v.legacyActive, 0)
+		v.userHash = make(map[[16]byte]indexTimePair, 1024)
+		return
+	}
 	for _, user := range v.users {
 		v.generateNewHashes(nowSec, user)
 	}
@@ -0,0 +1,247 @@
+package vmess
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/vmessocket/vmessocket/common"
+	"github.com/vmessocket/vmessocket/common/protocol"
+	"github.com/vmessocket/vmessocket/common/serial"
+	"github.com/vmessocket/vmessocket/common/uuid"
+	"github.com/vmessocket/vmessocket/proxy/vmess/aead"
+)
+
+func legacyHash(id *protocol.ID, ts int64) []byte {
+	idHash := protocol.DefaultIDHash(id.Bytes())
+	common.Must2(serial.WriteUint64(idHash, uint64(ts)))
+	return idHash.Sum(nil)
+}
+
+func newTestUser(email string, alterIDs uint16) *protocol.MemoryUser {
+	id := protocol.NewID(uuid.New())
+	return &protocol.MemoryUser{
+		Email: email,
+		Account: &MemoryAccount{
+			ID:       id,
+			AlterIDs: protocol.NewAlterIDs(id, alterIDs),
+		},
+	}
+}
+
+func newTestValidator(count int) (*TimedUserValidator, []*protocol.MemoryUser) {
+	validator := NewTimedUserValidator(protocol.DefaultIDHash)
+	users := make([]*protocol.MemoryUser, count)
+	for i := range users {
+		users[i] = newTestUser(fmt.Sprint("user", i, "@example.com"), 0)
+		common.Must(validator.Add(users[i]))
+	}
+	return validator, users
+}
+
+func userAuthID(user *protocol.MemoryUser, now int64) []byte {
+	authID := aead.CreateAuthID(user.Account.(*MemoryAccount).ID.CmdKey(), now)
+	return authID[:]
+}
+
+func BenchmarkTimedUserValidator(b *testing.B) {
+	for _, count := range []int{10000, 100000} {
+		validator, users := newTestValidator(count)
+		validator.DisableLegacy()
+		now := time.Now().Unix()
+		authIDs := make([][]byte, 1024)
+		for i := range authIDs {
+			authIDs[i] = userAuthID(users[(i*7919)%count], now)
+		}
+		b.Run(fmt.Sprintf("users=%d/cold", count), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, _, err := validator.GetAEAD(authIDs[i%len(authIDs)]); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("users=%d/hot", count), func(b *testing.B) {
+			authID := authIDs[0]
+			if _, _, err := validator.GetAEAD(authID); err != nil {
+				b.Fatal(err)
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, _, err := validator.GetAEAD(authID); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("users=%d/miss", count), func(b *testing.B) {
+			authID := uuid.New()
+			for i := 0; i < b.N; i++ {
+				if _, _, err := validator.GetAEAD(authID.Bytes()); err != aead.ErrNotFound {
+					b.Fatal("unexpected match")
+				}
+				if _, _, _, err := validator.Get(authID.Bytes()); err != ErrNotFound {
+					b.Fatal("unexpected legacy match")
+				}
+			}
+		})
+		common.Must(validator.Close())
+	}
+}
+
+func TestTimedUserValidatorGetAEAD(t *testing.T) {
+	validator, users := newTestValidator(8)
+	defer validator.Close()
+	now := time.Now().Unix()
+	unknown := newTestUser("unknown@example.com", 0)
+	testCases := []struct {
+		name   string
+		authID []byte
+		email  string
+		err    error
+	}{
+		{
+			name:   "first",
+			authID: userAuthID(users[0], now),
+			email:  users[0].Email,
+		},
+		{
+			name:   "last",
+			authID: userAuthID(users[7], now),
+			email:  users[7].Email,
+		},
+		{
+			name:   "expired",
+			authID: userAuthID(users[3], now-3600),
+			err:    aead.ErrNotFound,
+		},
+		{
+			name:   "unknown",
+			authID: userAuthID(unknown, now),
+			err:    aead.ErrNotFound,
+		},
+		{
+			name:   "legacy hash",
+			authID: legacyHash(users[2].Account.(*MemoryAccount).ID, now),
+			err:    aead.ErrNotFound,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			user, found, err := validator.GetAEAD(tc.authID)
+			if err != tc.err {
+				t.Fatalf("expected error %v, got %v", tc.err, err)
+			}
+			if found != (tc.err == nil) {
+				t.Fatalf("unexpected found: %v", found)
+			}
+			if found && user.Email != tc.email {
+				t.Fatalf("expected %s, got %s", tc.email, user.Email)
+			}
+		})
+	}
+	if !validator.Remove(users[0].Email) {
+		t.Fatal("failed to remove user")
+	}
+	if _, _, err := validator.GetAEAD(userAuthID(users[0], now)); err != aead.ErrNotFound {
+		t.Fatal("removed user still matches: ", err)
+	}
+}
+
+func TestTimedUserValidatorGetLegacy(t *testing.T) {
+	validator := NewTimedUserValidator(protocol.DefaultIDHash)
+	defer validator.Close()
+	user := newTestUser("legacy@example.com", 2)
+	common.Must(validator.Add(user))
+	if len(validator.userHash) != 0 {
+		t.Fatal("legacy hashes generated before first legacy lookup")
+	}
+	account := user.Account.(*MemoryAccount)
+	now := time.Now().Unix()
+	testCases := []struct {
+		name string
+		hash []byte
+		err  error
+	}{
+		{
+			name: "primary id",
+			hash: legacyHash(account.ID, now),
+		},
+		{
+			name: "alter id",
+			hash: legacyHash(account.AlterIDs[1], now-60),
+		},
+		{
+			name: "expired",
+			hash: legacyHash(account.ID, now-600),
+			err:  ErrNotFound,
+		},
+		{
+			name: "aead auth id",
+			hash: userAuthID(user, now),
+			err:  ErrNotFound,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			found, _, valid, err := validator.Get(tc.hash)
+			if err != tc.err {
+				t.Fatalf("expected error %v, got %v", tc.err, err)
+			}
+			if valid != (tc.err == nil) {
+				t.Fatalf("unexpected valid: %v", valid)
+			}
+			if valid && found.Email != user.Email {
+				t.Fatalf("expected %s, got %s", user.Email, found.Email)
+			}
+		})
+	}
+	if err := validator.BurnTaintFuse(legacyHash(account.ID, now)); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, _, err := validator.Get(legacyHash(account.ID, now)); err != ErrTainted {
+		t.Fatal("expected tainted hash, got ", err)
+	}
+}
+
+func TestTimedUserValidatorLegacyBuildWithConcurrentAdds(t *testing.T) {
+	validator, users := newTestValidator(200)
+	defer validator.Close()
+	now := time.Now().Unix()
+	added := make(chan *protocol.MemoryUser, 50)
+	go func() {
+		defer close(added)
+		for i := 0; i < cap(added); i++ {
+			user := newTestUser(fmt.Sprint("late", i, "@example.com"), 0)
+			common.Must(validator.Add(user))
+			added <- user
+		}
+	}()
+	if _, _, valid, _ := validator.Get(legacyHash(users[0].Account.(*MemoryAccount).ID, now)); !valid {
+		t.Fatal("legacy lookup failed for an existing user")
+	}
+	for user := range added {
+		users = append(users, user)
+	}
+	for _, user := range users {
+		if found, _, valid, err := validator.Get(legacyHash(user.Account.(*MemoryAccount).ID, now)); !valid || found.Email != user.Email {
+			t.Fatal("legacy lookup failed for ", user.Email, ": ", err)
+		}
+	}
+}
+
+func TestTimedUserValidatorLegacyDisabled(t *testing.T) {
+	validator := NewTimedUserValidator(protocol.DefaultIDHash)
+	defer validator.Close()
+	user := newTestUser("legacy@example.com", 2)
+	common.Must(validator.Add(user))
+	validator.DisableLegacy()
+	account := user.Account.(*MemoryAccount)
+	if _, _, valid, err := validator.Get(legacyHash(account.ID, time.Now().Unix())); valid || err != ErrNotFound {
+		t.Fatal("legacy lookup succeeded with legacy disabled")
+	}
+	if len(validator.userHash) != 0 || validator.legacyActive != 0 || validator.legacyLastUsed != 0 {
+		t.Fatal("legacy lookup generated hashes with legacy disabled")
+	}
+	if _, found, err := validator.GetAEAD(userAuthID(user, time.Now().Unix())); !found || err != nil {
+		t.Fatal("AEAD lookup failed with legacy disabled: ", err)
+	}
+}
@@ -0,0 +1,24 @@
+package grpc
+
+import (
+	"github.com/vmessocket/vmessocket/common"
+	"github.com/vmessocket/vmessocket/transport/internet"
+)
+
+const (
+	defaultServiceName = "GunService"
+	protocolName       = "grpc"
+)
+
+func (c *Config) getServiceName() string {
+	if c.ServiceName == "" {
+		return defaultServiceName
+	}
+	return c.ServiceName
+}
+
+func init() {
+	common.Must(internet.RegisterProtocolConfigCreator(protocolName, func() interface{} {
+		return new(Config)
+	}))
+}
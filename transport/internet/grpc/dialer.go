@@ -0,0 +1,102 @@
+package grpc
+
+import (
+	"context"
+	gonet "net"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/backoff"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/vmessocket/vmessocket/common"
+	"github.com/vmessocket/vmessocket/common/net"
+	"github.com/vmessocket/vmessocket/common/session"
+	"github.com/vmessocket/vmessocket/core"
+	"github.com/vmessocket/vmessocket/transport/internet"
+	"github.com/vmessocket/vmessocket/transport/internet/grpc/encoding"
+	"github.com/vmessocket/vmessocket/transport/internet/tls"
+)
+
+var (
+	globalClients      = make(map[clientKey]*grpc.ClientConn)
+	globalClientAccess sync.Mutex
+)
+
+type clientKey struct {
+	dest      net.Destination
+	host      string
+	tlsConfig *tls.Config
+	sockopt   *internet.SocketConfig
+}
+
+func Dial(ctx context.Context, dest net.Destination, streamSettings *internet.MemoryStreamConfig) (internet.Connection, error) {
+	grpcSettings := streamSettings.ProtocolSettings.(*Config)
+	newError("creating connection to ", dest).WriteToLog(session.ExportIDToError(ctx))
+	client, err := getGRPCClient(ctx, dest, grpcSettings, tls.ConfigFromStreamSettings(streamSettings), streamSettings.SocketSettings)
+	if err != nil {
+		return nil, newError("failed to create gRPC client to ", dest).Base(err)
+	}
+	if grpcSettings.MultiMode {
+		stream, err := encoding.NewTunMultiClient(ctx, client, grpcSettings.getServiceName())
+		if err != nil {
+			return nil, newError("failed to open gRPC multi stream to ", dest).Base(err)
+		}
+		return encoding.NewMultiHunkConn(stream, nil), nil
+	}
+	stream, err := encoding.NewTunClient(ctx, client, grpcSettings.getServiceName())
+	if err != nil {
+		return nil, newError("failed to open gRPC stream to ", dest).Base(err)
+	}
+	return encoding.NewHunkConn(stream, nil), nil
+}
+
+func getGRPCClient(ctx context.Context, dest net.Destination, grpcSettings *Config, tlsConfig *tls.Config, sockopt *internet.SocketConfig) (*grpc.ClientConn, error) {
+	globalClientAccess.Lock()
+	defer globalClientAccess.Unlock()
+	key := clientKey{
+		dest:      dest,
+		host:      grpcSettings.Host,
+		tlsConfig: tlsConfig,
+		sockopt:   sockopt,
+	}
+	if client, found := globalClients[key]; found && client.GetState() != connectivity.Shutdown {
+		return client, nil
+	}
+	detachedContext := core.ToBackgroundDetachedContext(ctx)
+	options := []grpc.DialOption{
+		grpc.WithConnectParams(grpc.ConnectParams{
+			Backoff: backoff.Config{
+				BaseDelay:  500 * time.Millisecond,
+				Multiplier: 1.5,
+				Jitter:     0.2,
+				MaxDelay:   19 * time.Second,
+			},
+			MinConnectTimeout: 5 * time.Second,
+		}),
+		grpc.WithContextDialer(func(gctx context.Context, addr string) (gonet.Conn, error) {
+			return internet.DialSystem(detachedContext, dest, sockopt)
+		}),
+	}
+	if tlsConfig == nil {
+		options = append(options, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	} else {
+		options = append(options, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig.GetTLSConfig(tls.WithDestination(dest), tls.WithNextProto("h2")))))
+	}
+	if len(grpcSettings.Host) > 0 {
+		options = append(options, grpc.WithAuthority(grpcSettings.Host))
+	}
+	client, err := grpc.Dial(dest.NetAddr(), options...)
+	if err != nil {
+		return nil, err
+	}
+	globalClients[key] = client
+	return client, nil
+}
+
+func init() {
+	common.Must(internet.RegisterTransportDialer(protocolName, Dial))
+}
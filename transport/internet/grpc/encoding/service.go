@@ -0,0 +1,37 @@
+package encoding
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+)
+
+func NewTunClient(ctx context.Context, cc grpc.ClientConnInterface, name string, opts ...grpc.CallOption) (GunService_TunClient, error) {
+	desc := ServiceDesc(name)
+	stream, err := cc.NewStream(ctx, &desc.Streams[0], "/"+name+"/Tun", opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &gunServiceTunClient{stream}, nil
+}
+
+func NewTunMultiClient(ctx context.Context, cc grpc.ClientConnInterface, name string, opts ...grpc.CallOption) (GunService_TunMultiClient, error) {
+	desc := ServiceDesc(name)
+	stream, err := cc.NewStream(ctx, &desc.Streams[1], "/"+name+"/TunMulti", opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &gunServiceTunMultiClient{stream}, nil
+}
+
+func RegisterGunServiceServerWithName(s grpc.ServiceRegistrar, srv GunServiceServer, name string) {
+	desc := ServiceDesc(name)
+	s.RegisterService(&desc, srv)
+}
+
+func ServiceDesc(name string) grpc.ServiceDesc {
+	desc := GunService_ServiceDesc
+	desc.ServiceName = name
+	desc.Streams = append([]grpc.StreamDesc(nil), GunService_ServiceDesc.Streams...)
+	return desc
+}
@@ -0,0 +1,155 @@
+package encoding
+
+import (
+	"context"
+	"io"
+
+	"google.golang.org/grpc/peer"
+
+	"github.com/vmessocket/vmessocket/common"
+	"github.com/vmessocket/vmessocket/common/buf"
+	"github.com/vmessocket/vmessocket/common/net"
+	"github.com/vmessocket/vmessocket/common/signal/done"
+)
+
+const maxMultiHunkSize = 512 * 1024
+
+type HunkStream interface {
+	Context() context.Context
+	Recv() (*Hunk, error)
+	Send(*Hunk) error
+}
+
+type MultiHunkStream interface {
+	Context() context.Context
+	Recv() (*MultiHunk, error)
+	Send(*MultiHunk) error
+}
+
+type streamCloser interface {
+	CloseSend() error
+}
+
+type tunnel struct {
+	stream interface{}
+	recv   func() ([][]byte, error)
+	send   func([][]byte) error
+	cancel context.CancelFunc
+	done   *done.Instance
+}
+
+func newConnection(ctx context.Context, t *tunnel) net.Conn {
+	options := []net.ConnectionOption{
+		net.ConnectionInputMulti(t),
+		net.ConnectionOutputMulti(t),
+		net.ConnectionOnClose(t),
+	}
+	if p, ok := peer.FromContext(ctx); ok {
+		options = append(options, net.ConnectionRemoteAddr(p.Addr))
+	}
+	return net.NewConnection(options...)
+}
+
+func NewHunkConn(stream HunkStream, cancel context.CancelFunc) net.Conn {
+	return newConnection(stream.Context(), &tunnel{
+		stream: stream,
+		recv: func() ([][]byte, error) {
+			hunk, err := stream.Recv()
+			if err != nil {
+				return nil, err
+			}
+			return [][]byte{hunk.Data}, nil
+		},
+		send: func(data [][]byte) error {
+			for _, b := range data {
+				if err := stream.Send(&Hunk{Data: b}); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		cancel: cancel,
+		done:   done.New(),
+	})
+}
+
+func NewMultiHunkConn(stream MultiHunkStream, cancel context.CancelFunc) net.Conn {
+	return newConnection(stream.Context(), &tunnel{
+		stream: stream,
+		recv: func() ([][]byte, error) {
+			hunk, err := stream.Recv()
+			if err != nil {
+				return nil, err
+			}
+			return hunk.Data, nil
+		},
+		send: func(data [][]byte) error {
+			for len(data) > 0 {
+				size, count := 0, 0
+				for count < len(data) && (count == 0 || size+len(data[count]) <= maxMultiHunkSize) {
+					size += len(data[count])
+					count++
+				}
+				if err := stream.Send(&MultiHunk{Data: data[:count]}); err != nil {
+					return err
+				}
+				data = data[count:]
+			}
+			return nil
+		},
+		cancel: cancel,
+		done:   done.New(),
+	})
+}
+
+func (t *tunnel) Close() error {
+	if t.done.Done() {
+		return nil
+	}
+	common.Must(t.done.Close())
+	if t.cancel != nil {
+		t.cancel()
+	}
+	if closer, ok := t.stream.(streamCloser); ok {
+		return closer.CloseSend()
+	}
+	return nil
+}
+
+func (t *tunnel) ReadMultiBuffer() (buf.MultiBuffer, error) {
+	if t.done.Done() {
+		return nil, io.EOF
+	}
+	data, err := t.recv()
+	if err != nil {
+		if err == io.EOF {
+			return nil, err
+		}
+		return nil, newError("failed to receive data from gRPC tunnel").Base(err)
+	}
+	var mb buf.MultiBuffer
+	for _, b := range data {
+		mb = buf.MergeBytes(mb, b)
+	}
+	return mb, nil
+}
+
+func (t *tunnel) WriteMultiBuffer(mb buf.MultiBuffer) error {
+	defer buf.ReleaseMulti(mb)
+	if t.done.Done() {
+		return io.ErrClosedPipe
+	}
+	data := make([][]byte, 0, len(mb))
+	for _, b := range mb {
+		if !b.IsEmpty() {
+			data = append(data, b.Bytes())
+		}
+	}
+	if len(data) == 0 {
+		return nil
+	}
+	if err := t.send(data); err != nil {
+		return newError("failed to send data over gRPC tunnel").Base(err)
+	}
+	return nil
+}
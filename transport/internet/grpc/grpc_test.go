@@ -0,0 +1,93 @@
+package grpc
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/vmessocket/vmessocket/common"
+	"github.com/vmessocket/vmessocket/common/net"
+	"github.com/vmessocket/vmessocket/transport/internet"
+)
+
+func echo(conn internet.Connection) {
+	go func() {
+		defer conn.Close()
+		io.Copy(conn, conn)
+	}()
+}
+
+func listenGRPC(t *testing.T, config *Config, handler internet.ConnHandler) net.Destination {
+	t.Helper()
+	listener, err := Listen(context.Background(), net.LocalHostIP, 0, &internet.MemoryStreamConfig{
+		ProtocolName:     protocolName,
+		ProtocolSettings: config,
+	}, handler)
+	common.Must(err)
+	t.Cleanup(func() {
+		listener.Close()
+	})
+	return net.DestinationFromAddr(listener.Addr())
+}
+
+func TestGRPCRoundTrip(t *testing.T) {
+	payload := make([]byte, 2*1024*1024)
+	common.Must2(rand.Read(payload))
+	testCases := []struct {
+		name   string
+		config *Config
+	}{
+		{"single", &Config{}},
+		{"multi", &Config{MultiMode: true}},
+		{"custom service name", &Config{ServiceName: "example.Tunnel"}},
+		{"custom service name multi", &Config{ServiceName: "example.Tunnel", MultiMode: true}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dest := listenGRPC(t, tc.config, echo)
+			conn, err := Dial(context.Background(), dest, &internet.MemoryStreamConfig{
+				ProtocolName:     protocolName,
+				ProtocolSettings: tc.config,
+			})
+			common.Must(err)
+			defer conn.Close()
+			go func() {
+				conn.Write(payload)
+			}()
+			received := make([]byte, len(payload))
+			conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+			if _, err := io.ReadFull(conn, received); err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(received, payload) {
+				t.Fatal("echoed data differs")
+			}
+		})
+	}
+}
+
+func TestGRPCServiceNameMismatch(t *testing.T) {
+	accepted := make(chan struct{}, 1)
+	dest := listenGRPC(t, &Config{ServiceName: "example.Tunnel"}, func(conn internet.Connection) {
+		accepted <- struct{}{}
+		conn.Close()
+	})
+	conn, err := Dial(context.Background(), dest, &internet.MemoryStreamConfig{
+		ProtocolName:     protocolName,
+		ProtocolSettings: &Config{ServiceName: "other.Tunnel"},
+	})
+	common.Must(err)
+	defer conn.Close()
+	conn.Write([]byte("ping"))
+	if _, err := conn.Read(make([]byte, 4)); err == nil {
+		t.Fatal("read over a stream to an unknown service")
+	}
+	select {
+	case <-accepted:
+		t.Fatal("listener accepted a stream for another service name")
+	default:
+	}
+}
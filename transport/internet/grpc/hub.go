@@ -0,0 +1,88 @@
+package grpc
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+
+	"github.com/vmessocket/vmessocket/common"
+	"github.com/vmessocket/vmessocket/common/net"
+	"github.com/vmessocket/vmessocket/common/session"
+	"github.com/vmessocket/vmessocket/transport/internet"
+	"github.com/vmessocket/vmessocket/transport/internet/grpc/encoding"
+	"github.com/vmessocket/vmessocket/transport/internet/tls"
+)
+
+type Listener struct {
+	encoding.UnimplementedGunServiceServer
+	ctx          context.Context
+	server       *grpc.Server
+	handler      internet.ConnHandler
+	local        net.Addr
+	certificates common.Closable
+}
+
+func Listen(ctx context.Context, address net.Address, port net.Port, streamSettings *internet.MemoryStreamConfig, handler internet.ConnHandler) (internet.Listener, error) {
+	grpcSettings := streamSettings.ProtocolSettings.(*Config)
+	listener, err := internet.ListenSystem(ctx, &net.TCPAddr{
+		IP:   address.IP(),
+		Port: int(port),
+	}, streamSettings.SocketSettings)
+	if err != nil {
+		return nil, newError("failed to listen TCP(for gRPC) on ", address, ":", port).Base(err)
+	}
+	l := &Listener{
+		ctx:     ctx,
+		handler: handler,
+		local:   listener.Addr(),
+	}
+	if config := tls.ConfigFromStreamSettings(streamSettings); config == nil {
+		l.server = grpc.NewServer()
+	} else {
+		tlsConfig, certificates := config.GetServerTLSConfig(tls.WithNextProto("h2"))
+		l.server = grpc.NewServer(grpc.Creds(credentials.NewTLS(tlsConfig)))
+		l.certificates = certificates
+	}
+	encoding.RegisterGunServiceServerWithName(l.server, l, grpcSettings.getServiceName())
+	newError("listening gRPC service ", grpcSettings.getServiceName(), " on ", address, ":", port).WriteToLog(session.ExportIDToError(ctx))
+	go func() {
+		if err := l.server.Serve(listener); err != nil {
+			newError("stopped serving gRPC on ", address, ":", port).Base(err).AtWarning().WriteToLog(session.ExportIDToError(ctx))
+		}
+	}()
+	return l, nil
+}
+
+func (l *Listener) Addr() net.Addr {
+	return l.local
+}
+
+func (l *Listener) Close() error {
+	l.server.Stop()
+	return common.Close(l.certificates)
+}
+
+func (l *Listener) Tun(stream encoding.GunService_TunServer) error {
+	ctx, cancel := context.WithCancel(l.ctx)
+	l.handler(encoding.NewHunkConn(stream, cancel))
+	select {
+	case <-ctx.Done():
+	case <-stream.Context().Done():
+	}
+	return nil
+}
+
+func (l *Listener) TunMulti(stream encoding.GunService_TunMultiServer) error {
+	ctx, cancel := context.WithCancel(l.ctx)
+	l.handler(encoding.NewMultiHunkConn(stream, cancel))
+	select {
+	case <-ctx.Done():
+	case <-stream.Context().Done():
+	}
+	return nil
+}
+
+func init() {
+	common.Must(internet.RegisterTransportListener(protocolName, Listen))
+}
@@ -0,0 +1,25 @@
+package internet
+
+import (
+	"context"
+	"net"
+
+	"github.com/vmessocket/vmessocket/common"
+)
+
+type ConnectionAuthenticator interface {
+	Client(net.Conn) net.Conn
+	Server(net.Conn) net.Conn
+}
+
+func CreateConnectionAuthenticator(config interface{}) (ConnectionAuthenticator, error) {
+	auth, err := common.CreateObject(context.Background(), config)
+	if err != nil {
+		return nil, err
+	}
+	a, ok := auth.(ConnectionAuthenticator)
+	if !ok {
+		return nil, newError("not a ConnectionAuthenticator")
+	}
+	return a, nil
+}
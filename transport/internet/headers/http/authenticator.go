@@ -0,0 +1,135 @@
+package http
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/vmessocket/vmessocket/common"
+	"github.com/vmessocket/vmessocket/common/buf"
+)
+
+var (
+	resp400 = &ResponseConfig{
+		Version: &Version{
+			Value: "1.1",
+		},
+		Status: &Status{
+			Code:   "400",
+			Reason: "Bad Request",
+		},
+		Header: []*Header{
+			{
+				Name:  "Connection",
+				Value: []string{"close"},
+			},
+			{
+				Name:  "Cache-Control",
+				Value: []string{"private"},
+			},
+			{
+				Name:  "Content-Length",
+				Value: []string{"0"},
+			},
+		},
+	}
+	resp404 = &ResponseConfig{
+		Version: &Version{
+			Value: "1.1",
+		},
+		Status: &Status{
+			Code:   "404",
+			Reason: "Not Found",
+		},
+		Header: []*Header{
+			{
+				Name:  "Connection",
+				Value: []string{"close"},
+			},
+			{
+				Name:  "Cache-Control",
+				Value: []string{"private"},
+			},
+			{
+				Name:  "Content-Length",
+				Value: []string{"0"},
+			},
+		},
+	}
+)
+
+type Authenticator struct {
+	config *Config
+}
+
+func formResponseHeader(config *ResponseConfig) *HeaderWriter {
+	header := buf.New()
+	common.Must2(header.WriteString(strings.Join([]string{config.GetFullVersion(), config.GetStatusValue().Code, config.GetStatusValue().Reason}, " ")))
+	common.Must2(header.WriteString(CRLF))
+	headers := config.PickHeaders()
+	for _, h := range headers {
+		common.Must2(header.WriteString(h))
+		common.Must2(header.WriteString(CRLF))
+	}
+	if !config.HasHeader("Date") {
+		common.Must2(header.WriteString("Date: "))
+		common.Must2(header.WriteString(time.Now().Format(http.TimeFormat)))
+		common.Must2(header.WriteString(CRLF))
+	}
+	common.Must2(header.WriteString(CRLF))
+	return NewHeaderWriter(header)
+}
+
+func NewAuthenticator(ctx context.Context, config *Config) (Authenticator, error) {
+	return Authenticator{
+		config: config,
+	}, nil
+}
+
+func (a Authenticator) Client(conn net.Conn) net.Conn {
+	if a.config.Request == nil && a.config.Response == nil {
+		return conn
+	}
+	var reader Reader = new(NoOpReader)
+	if a.config.Response != nil {
+		reader = new(HeaderReader)
+	}
+	var writer Writer = new(NoOpWriter)
+	if a.config.Request != nil {
+		writer = a.GetClientWriter()
+	}
+	return NewConn(conn, reader, writer, new(NoOpWriter), new(NoOpWriter), new(NoOpWriter))
+}
+
+func (a Authenticator) GetClientWriter() *HeaderWriter {
+	header := buf.New()
+	config := a.config.Request
+	common.Must2(header.WriteString(strings.Join([]string{config.GetMethodValue(), config.PickURI(), config.GetFullVersion()}, " ")))
+	common.Must2(header.WriteString(CRLF))
+	headers := config.PickHeaders()
+	for _, h := range headers {
+		common.Must2(header.WriteString(h))
+		common.Must2(header.WriteString(CRLF))
+	}
+	common.Must2(header.WriteString(CRLF))
+	return NewHeaderWriter(header)
+}
+
+func (a Authenticator) GetServerWriter() *HeaderWriter {
+	return formResponseHeader(a.config.Response)
+}
+
+func (a Authenticator) Server(conn net.Conn) net.Conn {
+	if a.config.Request == nil && a.config.Response == nil {
+		return conn
+	}
+	return NewConn(conn, new(HeaderReader).ExpectThisRequest(a.config.Request), a.GetServerWriter(), formResponseHeader(resp400), formResponseHeader(resp404), formResponseHeader(resp400))
+}
+
+func init() {
+	common.Must(common.RegisterConfig((*Config)(nil), func(ctx context.Context, config interface{}) (interface{}, error) {
+		return NewAuthenticator(ctx, config.(*Config))
+	}))
+}
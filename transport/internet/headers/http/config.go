@@ -0,0 +1,101 @@
+package http
+
+import (
+	"strings"
+
+	"github.com/vmessocket/vmessocket/common/dice"
+)
+
+func pickHeaders(headers []*Header) []string {
+	picked := make([]string, 0, len(headers))
+	for _, header := range headers {
+		picked = append(picked, header.Name+": "+pickString(header.Value))
+	}
+	return picked
+}
+
+func pickString(values []string) string {
+	switch len(values) {
+	case 0:
+		return ""
+	case 1:
+		return values[0]
+	default:
+		return values[dice.Roll(len(values))]
+	}
+}
+
+func (c *RequestConfig) GetFullVersion() string {
+	return "HTTP/" + c.GetVersionValue()
+}
+
+func (c *ResponseConfig) GetFullVersion() string {
+	return "HTTP/" + c.GetVersionValue()
+}
+
+func (c *RequestConfig) GetMethodValue() string {
+	if c == nil || c.Method == nil {
+		return "GET"
+	}
+	return c.Method.Value
+}
+
+func (c *ResponseConfig) GetStatusValue() *Status {
+	if c == nil || c.Status == nil {
+		return &Status{
+			Code:   "200",
+			Reason: "OK",
+		}
+	}
+	return c.Status
+}
+
+func (c *RequestConfig) GetVersionValue() string {
+	if c == nil || c.Version == nil {
+		return "1.1"
+	}
+	return c.Version.Value
+}
+
+func (c *ResponseConfig) GetVersionValue() string {
+	if c == nil || c.Version == nil {
+		return "1.1"
+	}
+	return c.Version.Value
+}
+
+func (c *ResponseConfig) HasHeader(name string) bool {
+	for _, header := range c.GetHeader() {
+		if strings.EqualFold(header.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *RequestConfig) hasURI(uri string) bool {
+	if len(c.GetUri()) == 0 {
+		return uri == "/"
+	}
+	for _, u := range c.Uri {
+		if u == uri {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *RequestConfig) PickHeaders() []string {
+	return pickHeaders(c.GetHeader())
+}
+
+func (c *ResponseConfig) PickHeaders() []string {
+	return pickHeaders(c.GetHeader())
+}
+
+func (c *RequestConfig) PickURI() string {
+	if len(c.GetUri()) == 0 {
+		return "/"
+	}
+	return pickString(c.Uri)
+}
@@ -0,0 +1,181 @@
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/vmessocket/vmessocket/common"
+	"github.com/vmessocket/vmessocket/common/buf"
+)
+
+const (
+	CRLF            = "\r\n"
+	ENDING          = CRLF + CRLF
+	maxHeaderLength = 8192
+)
+
+var (
+	ErrHeaderMisMatch = errors.New("header mismatch")
+	ErrHeaderToLong   = errors.New("header too long")
+)
+
+type Conn struct {
+	net.Conn
+	readBuffer          *buf.Buffer
+	oneTimeReader       Reader
+	oneTimeWriter       Writer
+	errorWriter         Writer
+	errorMismatchWriter Writer
+	errorTooLongWriter  Writer
+	errReason           error
+	closeOnce           sync.Once
+}
+
+type HeaderReader struct {
+	req *RequestConfig
+}
+
+type HeaderWriter struct {
+	header *buf.Buffer
+}
+
+type NoOpReader struct{}
+
+type NoOpWriter struct{}
+
+type Reader interface {
+	Read(io.Reader) (*buf.Buffer, error)
+}
+
+type Writer interface {
+	Write(io.Writer) error
+}
+
+func NewConn(conn net.Conn, reader Reader, writer Writer, errorWriter Writer, errorMismatchWriter Writer, errorTooLongWriter Writer) *Conn {
+	return &Conn{
+		Conn:                conn,
+		oneTimeReader:       reader,
+		oneTimeWriter:       writer,
+		errorWriter:         errorWriter,
+		errorMismatchWriter: errorMismatchWriter,
+		errorTooLongWriter:  errorTooLongWriter,
+	}
+}
+
+func NewHeaderWriter(header *buf.Buffer) *HeaderWriter {
+	return &HeaderWriter{
+		header: header,
+	}
+}
+
+func (c *Conn) Close() error {
+	c.closeOnce.Do(func() {
+		if c.oneTimeWriter != nil && c.errorWriter != nil {
+			writer := c.errorWriter
+			switch c.errReason {
+			case ErrHeaderMisMatch:
+				writer = c.errorMismatchWriter
+			case ErrHeaderToLong:
+				writer = c.errorTooLongWriter
+			}
+			if writer != nil {
+				c.Conn.SetWriteDeadline(time.Now().Add(time.Second))
+				writer.Write(c.Conn)
+			}
+		}
+	})
+	return c.Conn.Close()
+}
+
+func (h *HeaderReader) ExpectThisRequest(expectation *RequestConfig) *HeaderReader {
+	h.req = expectation
+	return h
+}
+
+func (c *Conn) Read(b []byte) (int, error) {
+	if c.oneTimeReader != nil {
+		buffer, err := c.oneTimeReader.Read(c.Conn)
+		if err != nil {
+			c.errReason = err
+			return 0, err
+		}
+		c.readBuffer = buffer
+		c.oneTimeReader = nil
+	}
+	if !c.readBuffer.IsEmpty() {
+		nBytes, _ := c.readBuffer.Read(b)
+		if c.readBuffer.IsEmpty() {
+			c.readBuffer.Release()
+			c.readBuffer = nil
+		}
+		return nBytes, nil
+	}
+	return c.Conn.Read(b)
+}
+
+func (h *HeaderReader) Read(reader io.Reader) (*buf.Buffer, error) {
+	var header bytes.Buffer
+	chunk := buf.New()
+	defer chunk.Release()
+	ending := -1
+	for ending == -1 {
+		chunk.Clear()
+		if _, err := chunk.ReadFrom(reader); err != nil {
+			return nil, err
+		}
+		header.Write(chunk.Bytes())
+		if n := bytes.Index(header.Bytes(), []byte(ENDING)); n != -1 {
+			ending = n + len(ENDING)
+		}
+		if ending > maxHeaderLength || (ending == -1 && header.Len() > maxHeaderLength) {
+			return nil, ErrHeaderToLong
+		}
+	}
+	if h.req != nil {
+		req, err := http.ReadRequest(bufio.NewReader(bytes.NewReader(header.Bytes()[:ending])))
+		if err != nil || !h.req.hasURI(req.URL.Path) {
+			return nil, ErrHeaderMisMatch
+		}
+	}
+	if header.Len() == ending {
+		return nil, nil
+	}
+	buffer := buf.New()
+	common.Must2(buffer.Write(header.Bytes()[ending:]))
+	return buffer, nil
+}
+
+func (*NoOpReader) Read(io.Reader) (*buf.Buffer, error) {
+	return nil, nil
+}
+
+func (c *Conn) Write(b []byte) (int, error) {
+	if c.oneTimeWriter != nil {
+		err := c.oneTimeWriter.Write(c.Conn)
+		c.oneTimeWriter = nil
+		if err != nil {
+			return 0, err
+		}
+	}
+	return c.Conn.Write(b)
+}
+
+func (w *HeaderWriter) Write(writer io.Writer) error {
+	if w.header == nil {
+		return nil
+	}
+	err := buf.WriteAllBytes(writer, w.header.Bytes())
+	w.header.Release()
+	w.header = nil
+	return err
+}
+
+func (*NoOpWriter) Write(io.Writer) error {
+	return nil
+}
@@ -0,0 +1,95 @@
+package http
+
+import (
+	"bytes"
+	"io"
+	gonet "net"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/vmessocket/vmessocket/common"
+)
+
+func TestHeaderReaderRead(t *testing.T) {
+	expectation := &RequestConfig{
+		Uri: []string{"/", "/path"},
+	}
+	padding := strings.Repeat("a", maxHeaderLength)
+	testCases := []struct {
+		input   string
+		payload string
+		err     error
+	}{
+		{"GET / HTTP/1.1\r\nHost: example.com\r\n\r\n", "", nil},
+		{"GET /path HTTP/1.1\r\nHost: example.com\r\n\r\npayload", "payload", nil},
+		{"GET /path?query HTTP/1.1\r\n\r\n", "", nil},
+		{"", "", io.EOF},
+		{"GET / HTTP/1.1\r\nHost: example.com\r\n", "", io.EOF},
+		{"GET /other HTTP/1.1\r\nHost: example.com\r\n\r\n", "", ErrHeaderMisMatch},
+		{"SSH-2.0-OpenSSH_8.9\r\n\r\n", "", ErrHeaderMisMatch},
+		{"GET / HTTP/x\r\n\r\n", "", ErrHeaderMisMatch},
+		{"\r\n\r\n", "", ErrHeaderMisMatch},
+		{"GET / HTTP/1.1\r\nHost example.com\r\n\r\n", "", ErrHeaderMisMatch},
+		{"GET / HTTP/1.1\r\nX-Padding: " + padding + "\r\n\r\n", "", ErrHeaderToLong},
+	}
+	for _, tc := range testCases {
+		for _, oneByte := range []bool{false, true} {
+			var reader io.Reader = strings.NewReader(tc.input)
+			if oneByte {
+				reader = iotest.OneByteReader(reader)
+			}
+			buffer, err := new(HeaderReader).ExpectThisRequest(expectation).Read(reader)
+			if err != tc.err {
+				t.Errorf("%.40q (one byte reads: %v): error %v, want %v", tc.input, oneByte, err, tc.err)
+				continue
+			}
+			var payload string
+			if buffer != nil {
+				payload = buffer.String()
+				buffer.Release()
+			}
+			if err == nil && payload != tc.payload && !oneByte {
+				t.Errorf("%.40q: payload %q, want %q", tc.input, payload, tc.payload)
+			}
+		}
+	}
+}
+
+func TestHeaderReaderWithoutExpectation(t *testing.T) {
+	buffer, err := new(HeaderReader).Read(strings.NewReader("HTTP/1.1 200 OK\r\n\r\ndata"))
+	common.Must(err)
+	defer buffer.Release()
+	if buffer.String() != "data" {
+		t.Errorf("payload %q, want %q", buffer.String(), "data")
+	}
+}
+
+func TestServerRejectsMalformedRequest(t *testing.T) {
+	auth, err := NewAuthenticator(nil, &Config{
+		Request:  &RequestConfig{},
+		Response: &ResponseConfig{},
+	})
+	common.Must(err)
+	for input, status := range map[string]string{
+		"GET /other HTTP/1.1\r\n\r\n":          "HTTP/1.1 404 Not Found\r\n",
+		strings.Repeat("a", maxHeaderLength+1): "HTTP/1.1 400 Bad Request\r\n",
+	} {
+		client, server := gonet.Pipe()
+		conn := auth.Server(server)
+		go client.Write([]byte(input))
+		if _, err := conn.Read(make([]byte, 16)); err == nil {
+			t.Errorf("%.40q: read succeeded", input)
+		}
+		response := make(chan []byte, 1)
+		go func() {
+			b, _ := io.ReadAll(client)
+			response <- b
+		}()
+		conn.Close()
+		if b := <-response; !bytes.HasPrefix(b, []byte(status)) {
+			t.Errorf("%.40q: response %q, want %q", input, b, status)
+		}
+		client.Close()
+	}
+}
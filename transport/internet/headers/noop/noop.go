@@ -0,0 +1,24 @@
+package noop
+
+import (
+	"context"
+	"net"
+
+	"github.com/vmessocket/vmessocket/common"
+)
+
+type ConnectionHeader struct{}
+
+func (ConnectionHeader) Client(conn net.Conn) net.Conn {
+	return conn
+}
+
+func (ConnectionHeader) Server(conn net.Conn) net.Conn {
+	return conn
+}
+
+func init() {
+	common.Must(common.RegisterConfig((*ConnectionConfig)(nil), func(ctx context.Context, config interface{}) (interface{}, error) {
+		return ConnectionHeader{}, nil
+	}))
+}
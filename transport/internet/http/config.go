@@ -0,0 +1,63 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/vmessocket/vmessocket/common"
+	"github.com/vmessocket/vmessocket/common/dice"
+	"github.com/vmessocket/vmessocket/transport/internet"
+)
+
+const protocolName = "http"
+
+func (c *Config) getHeader() http.Header {
+	header := http.Header{}
+	for _, h := range c.Header {
+		header.Add(h.Key, h.Value)
+	}
+	return header
+}
+
+func (c *Config) getMethod() string {
+	if c.Method == "" {
+		return http.MethodPut
+	}
+	return c.Method
+}
+
+func (c *Config) getNormalizedPath() string {
+	path := c.Path
+	if path == "" {
+		return "/"
+	}
+	if path[0] != '/' {
+		return "/" + path
+	}
+	return path
+}
+
+func (c *Config) getRandomHost() string {
+	if len(c.Host) == 0 {
+		return ""
+	}
+	return c.Host[dice.Roll(len(c.Host))]
+}
+
+func (c *Config) isValidHost(host string) bool {
+	if len(c.Host) == 0 {
+		return true
+	}
+	for _, h := range c.Host {
+		if strings.EqualFold(h, host) {
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	common.Must(internet.RegisterProtocolConfigCreator(protocolName, func() interface{} {
+		return new(Config)
+	}))
+}
@@ -0,0 +1,207 @@
+package http
+
+import (
+	"context"
+	gotls "crypto/tls"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+
+	"golang.org/x/net/http2"
+
+	"github.com/vmessocket/vmessocket/common"
+	"github.com/vmessocket/vmessocket/common/buf"
+	"github.com/vmessocket/vmessocket/common/net"
+	"github.com/vmessocket/vmessocket/common/session"
+	"github.com/vmessocket/vmessocket/core"
+	"github.com/vmessocket/vmessocket/transport/internet"
+	"github.com/vmessocket/vmessocket/transport/internet/tls"
+	"github.com/vmessocket/vmessocket/transport/pipe"
+)
+
+var (
+	globalClients      = make(map[clientKey]*http.Client)
+	globalClientAccess sync.Mutex
+)
+
+type clientKey struct {
+	dest      net.Destination
+	tlsConfig *tls.Config
+	sockopt   *internet.SocketConfig
+}
+
+type waitReadCloser struct {
+	access sync.Mutex
+	ready  chan struct{}
+	closed bool
+	body   io.ReadCloser
+	err    error
+}
+
+func Dial(ctx context.Context, dest net.Destination, streamSettings *internet.MemoryStreamConfig) (internet.Connection, error) {
+	httpSettings := streamSettings.ProtocolSettings.(*Config)
+	tlsConfig := tls.ConfigFromStreamSettings(streamSettings)
+	key := clientKey{
+		dest:      dest,
+		tlsConfig: tlsConfig,
+		sockopt:   streamSettings.SocketSettings,
+	}
+	client := getHTTPClient(ctx, key)
+	scheme := "https"
+	if tlsConfig == nil {
+		scheme = "http"
+	}
+	preader, pwriter := pipe.New(pipe.OptionsFromContext(ctx)...)
+	breader := &buf.BufferedReader{Reader: preader}
+	request := &http.Request{
+		Method: httpSettings.getMethod(),
+		Host:   httpSettings.getRandomHost(),
+		Body:   breader,
+		URL: &url.URL{
+			Scheme: scheme,
+			Host:   dest.NetAddr(),
+			Path:   httpSettings.getNormalizedPath(),
+		},
+		Proto:      "HTTP/2",
+		ProtoMajor: 2,
+		ProtoMinor: 0,
+		Header:     httpSettings.getHeader(),
+	}
+	wrc := newWaitReadCloser()
+	go func() {
+		response, err := client.Do(request)
+		if err != nil {
+			dialErr := newError("failed to dial to ", dest).Base(err).AtWarning()
+			dialErr.WriteToLog(session.ExportIDToError(ctx))
+			removeHTTPClient(key, client)
+			wrc.fail(dialErr)
+			return
+		}
+		if response.StatusCode != http.StatusOK {
+			err := newError("unexpected status ", response.StatusCode, " from ", dest).AtWarning()
+			err.WriteToLog(session.ExportIDToError(ctx))
+			response.Body.Close()
+			wrc.fail(err)
+			return
+		}
+		wrc.set(response.Body)
+	}()
+	bwriter := buf.NewBufferedWriter(pwriter)
+	common.Must(bwriter.SetBuffered(false))
+	return net.NewConnection(
+		net.ConnectionOutput(wrc),
+		net.ConnectionInput(bwriter),
+		net.ConnectionOnClose(common.ChainedClosable{breader, bwriter, wrc}),
+	), nil
+}
+
+func getHTTPClient(ctx context.Context, key clientKey) *http.Client {
+	globalClientAccess.Lock()
+	defer globalClientAccess.Unlock()
+	dest := key.dest
+	tlsConfig := key.tlsConfig
+	sockopt := key.sockopt
+	if client, found := globalClients[key]; found {
+		return client
+	}
+	detachedContext := core.ToBackgroundDetachedContext(ctx)
+	transport := &http2.Transport{
+		DialTLS: func(network string, addr string, config *gotls.Config) (net.Conn, error) {
+			conn, err := internet.DialSystem(detachedContext, dest, sockopt)
+			if err != nil {
+				return nil, err
+			}
+			if tlsConfig == nil {
+				return conn, nil
+			}
+			tlsConn := gotls.Client(conn, config)
+			if err := tlsConn.Handshake(); err != nil {
+				conn.Close()
+				return nil, err
+			}
+			if p := tlsConn.ConnectionState().NegotiatedProtocol; p != http2.NextProtoTLS {
+				tlsConn.Close()
+				return nil, newError("unexpected ALPN protocol ", p, ", want ", http2.NextProtoTLS)
+			}
+			return tlsConn, nil
+		},
+		AllowHTTP: tlsConfig == nil,
+	}
+	if tlsConfig != nil {
+		transport.TLSClientConfig = tlsConfig.GetTLSConfig(tls.WithDestination(dest), tls.WithNextProto(http2.NextProtoTLS))
+	}
+	client := &http.Client{
+		Transport: transport,
+	}
+	globalClients[key] = client
+	return client
+}
+
+func newWaitReadCloser() *waitReadCloser {
+	return &waitReadCloser{
+		ready: make(chan struct{}),
+	}
+}
+
+func removeHTTPClient(key clientKey, client *http.Client) {
+	globalClientAccess.Lock()
+	if globalClients[key] == client {
+		delete(globalClients, key)
+	}
+	globalClientAccess.Unlock()
+	client.CloseIdleConnections()
+}
+
+func (w *waitReadCloser) Close() error {
+	w.access.Lock()
+	defer w.access.Unlock()
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	if w.body != nil {
+		return w.body.Close()
+	}
+	close(w.ready)
+	return nil
+}
+
+func (w *waitReadCloser) fail(err error) {
+	w.access.Lock()
+	if w.body == nil && !w.closed {
+		w.err = err
+	}
+	w.access.Unlock()
+	w.Close()
+}
+
+func (w *waitReadCloser) Read(b []byte) (int, error) {
+	<-w.ready
+	w.access.Lock()
+	body := w.body
+	err := w.err
+	w.access.Unlock()
+	if body == nil {
+		if err != nil {
+			return 0, err
+		}
+		return 0, io.ErrClosedPipe
+	}
+	return body.Read(b)
+}
+
+func (w *waitReadCloser) set(body io.ReadCloser) {
+	w.access.Lock()
+	defer w.access.Unlock()
+	if w.closed {
+		body.Close()
+		return
+	}
+	w.body = body
+	close(w.ready)
+}
+
+func init() {
+	common.Must(internet.RegisterTransportDialer(protocolName, Dial))
+}
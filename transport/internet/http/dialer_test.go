@@ -0,0 +1,36 @@
+package http
+
+import (
+	"context"
+	"io"
+	gonet "net"
+	"testing"
+
+	"github.com/vmessocket/vmessocket/common"
+	"github.com/vmessocket/vmessocket/common/net"
+	"github.com/vmessocket/vmessocket/transport/internet"
+)
+
+func TestDialFailureEvictsClient(t *testing.T) {
+	l, err := gonet.Listen("tcp", "127.0.0.1:0")
+	common.Must(err)
+	dest := net.DestinationFromAddr(l.Addr())
+	common.Must(l.Close())
+	streamSettings := &internet.MemoryStreamConfig{
+		ProtocolName:     protocolName,
+		ProtocolSettings: &Config{},
+	}
+	conn, err := Dial(context.Background(), dest, streamSettings)
+	common.Must(err)
+	defer conn.Close()
+	conn.Write([]byte("ping"))
+	_, err = conn.Read(make([]byte, 16))
+	if err == nil || err == io.ErrClosedPipe {
+		t.Fatal("expected the dial error, got ", err)
+	}
+	globalClientAccess.Lock()
+	defer globalClientAccess.Unlock()
+	if _, found := globalClients[clientKey{dest: dest}]; found {
+		t.Error("broken client is still cached")
+	}
+}
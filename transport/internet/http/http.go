@@ -0,0 +1,3 @@
+package http
+
+//go:generate go run github.com/vmessocket/vmessocket/common/errors/errorgen
@@ -0,0 +1,149 @@
+package http
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+
+	"github.com/vmessocket/vmessocket/common"
+	"github.com/vmessocket/vmessocket/common/net"
+	http_proto "github.com/vmessocket/vmessocket/common/protocol/http"
+	"github.com/vmessocket/vmessocket/common/session"
+	"github.com/vmessocket/vmessocket/common/signal/done"
+	"github.com/vmessocket/vmessocket/transport/internet"
+	"github.com/vmessocket/vmessocket/transport/internet/tls"
+)
+
+type flushWriter struct {
+	w io.Writer
+	d *done.Instance
+}
+
+type Listener struct {
+	server         *http.Server
+	handler        internet.ConnHandler
+	local          net.Addr
+	config         *Config
+	trustedProxies []*net.IPNet
+	certificates   common.Closable
+}
+
+func Listen(ctx context.Context, address net.Address, port net.Port, streamSettings *internet.MemoryStreamConfig, handler internet.ConnHandler) (internet.Listener, error) {
+	httpSettings := streamSettings.ProtocolSettings.(*Config)
+	trustedProxies, err := http_proto.ParseTrustedProxies(httpSettings.TrustedProxies)
+	if err != nil {
+		return nil, err
+	}
+	listener, err := internet.ListenSystem(ctx, &net.TCPAddr{
+		IP:   address.IP(),
+		Port: int(port),
+	}, streamSettings.SocketSettings)
+	if err != nil {
+		return nil, newError("failed to listen TCP(for HTTP/2) on ", address, ":", port).Base(err)
+	}
+	l := &Listener{
+		handler:        handler,
+		local:          listener.Addr(),
+		config:         httpSettings,
+		trustedProxies: trustedProxies,
+	}
+	l.server = &http.Server{
+		Handler:           l,
+		ReadHeaderTimeout: time.Second * 4,
+		MaxHeaderBytes:    http.DefaultMaxHeaderBytes,
+	}
+	config := tls.ConfigFromStreamSettings(streamSettings)
+	if config == nil {
+		l.server.Handler = h2c.NewHandler(l, &http2.Server{})
+		newError("listening h2c on ", address, ":", port).WriteToLog(session.ExportIDToError(ctx))
+	} else {
+		l.server.TLSConfig, l.certificates = config.GetServerTLSConfig(tls.WithNextProto(http2.NextProtoTLS))
+		newError("listening HTTP/2 on ", address, ":", port).WriteToLog(session.ExportIDToError(ctx))
+	}
+	go func() {
+		if config == nil {
+			err = l.server.Serve(listener)
+		} else {
+			err = l.server.ServeTLS(listener, "", "")
+		}
+		if err != nil && err != http.ErrServerClosed {
+			newError("stopped serving HTTP/2 on ", address, ":", port).Base(err).AtWarning().WriteToLog(session.ExportIDToError(ctx))
+		}
+	}()
+	return l, nil
+}
+
+func (l *Listener) Addr() net.Addr {
+	return l.local
+}
+
+func (l *Listener) Close() error {
+	common.Close(l.certificates)
+	return l.server.Close()
+}
+
+func (l *Listener) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	if !l.config.isValidHost(request.Host) {
+		writer.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if !strings.HasPrefix(request.URL.Path, l.config.getNormalizedPath()) {
+		writer.WriteHeader(http.StatusNotFound)
+		return
+	}
+	writer.Header().Set("Cache-Control", "no-store")
+	for _, h := range l.config.Header {
+		writer.Header().Add(h.Key, h.Value)
+	}
+	writer.WriteHeader(http.StatusOK)
+	if f, ok := writer.(http.Flusher); ok {
+		f.Flush()
+	}
+	remoteAddr := l.Addr()
+	dest, err := net.ParseDestination(request.RemoteAddr)
+	if err != nil {
+		newError("failed to parse request remote addr: ", request.RemoteAddr).Base(err).WriteToLog()
+	} else {
+		remoteAddr = &net.TCPAddr{
+			IP:   dest.Address.IP(),
+			Port: int(dest.Port),
+		}
+	}
+	remoteAddr = http_proto.ResolveRemoteAddr(request.Header, remoteAddr, l.trustedProxies)
+	d := done.New()
+	conn := net.NewConnection(
+		net.ConnectionOutput(request.Body),
+		net.ConnectionInput(flushWriter{w: writer, d: d}),
+		net.ConnectionOnClose(common.ChainedClosable{d, request.Body}),
+		net.ConnectionLocalAddr(l.Addr()),
+		net.ConnectionRemoteAddr(remoteAddr),
+	)
+	l.handler(conn)
+	<-d.Wait()
+}
+
+func (fw flushWriter) Write(p []byte) (n int, err error) {
+	if fw.d.Done() {
+		return 0, io.ErrClosedPipe
+	}
+	defer func() {
+		if recover() != nil {
+			fw.d.Close()
+			err = io.ErrClosedPipe
+		}
+	}()
+	n, err = fw.w.Write(p)
+	if f, ok := fw.w.(http.Flusher); ok && err == nil {
+		f.Flush()
+	}
+	return
+}
+
+func init() {
+	common.Must(internet.RegisterTransportListener(protocolName, Listen))
+}
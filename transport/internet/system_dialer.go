@@ -121,6 +121,10 @@ func (c *packetConnWrapper) Read(p []byte) (int, error) {
 	return n, err
 }
 
+func (c *packetConnWrapper) ReadFrom(p []byte) (int, net.Addr, error) {
+	return c.conn.ReadFrom(p)
+}
+
 func (c *packetConnWrapper) RemoteAddr() net.Addr {
 	return c.dest
 }
@@ -140,3 +144,7 @@ func (c *packetConnWrapper) SetWriteDeadline(t time.Time) error {
 func (c *packetConnWrapper) Write(p []byte) (int, error) {
 	return c.conn.WriteTo(p, c.dest)
 }
+
+func (c *packetConnWrapper) WriteTo(p []byte, addr net.Addr) (int, error) {
+	return c.conn.WriteTo(p, addr)
+}
@@ -5,8 +5,10 @@ import (
 
 	"github.com/vmessocket/vmessocket/common"
 	"github.com/vmessocket/vmessocket/common/net"
+	"github.com/vmessocket/vmessocket/common/protocol/proxyproto"
 	"github.com/vmessocket/vmessocket/common/session"
 	"github.com/vmessocket/vmessocket/transport/internet"
+	"github.com/vmessocket/vmessocket/transport/internet/tls"
 )
 
 func Dial(ctx context.Context, dest net.Destination, streamSettings *internet.MemoryStreamConfig) (internet.Connection, error) {
@@ -15,6 +17,30 @@ func Dial(ctx context.Context, dest net.Destination, streamSettings *internet.Me
 	if err != nil {
 		return nil, err
 	}
+	tcpSettings := streamSettings.ProtocolSettings.(*Config)
+	if tcpSettings.SendProxyProtocol > 0 {
+		if err := proxyproto.WriteHeader(ctx, conn, tcpSettings.SendProxyProtocol, conn.RemoteAddr()); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	if config := tls.ConfigFromStreamSettings(streamSettings); config != nil {
+		tlsConfig := config.GetTLSConfig(tls.WithDestination(dest))
+		conn = tls.Client(conn, tlsConfig)
+	}
+	if tcpSettings.HeaderSettings != nil {
+		headerConfig, err := tcpSettings.HeaderSettings.GetInstance()
+		if err != nil {
+			conn.Close()
+			return nil, newError("failed to get header settings").Base(err).AtError()
+		}
+		auth, err := internet.CreateConnectionAuthenticator(headerConfig)
+		if err != nil {
+			conn.Close()
+			return nil, newError("failed to create header authenticator").Base(err).AtError()
+		}
+		conn = auth.Client(conn)
+	}
 	return internet.Connection(conn), nil
 }
 
@@ -8,15 +8,19 @@ import (
 
 	"github.com/vmessocket/vmessocket/common"
 	"github.com/vmessocket/vmessocket/common/net"
+	"github.com/vmessocket/vmessocket/common/protocol/proxyproto"
 	"github.com/vmessocket/vmessocket/common/session"
 	"github.com/vmessocket/vmessocket/transport/internet"
+	"github.com/vmessocket/vmessocket/transport/internet/tls"
 )
 
 type Listener struct {
-	listener   net.Listener
-	tlsConfig  *gotls.Config
-	config     *Config
-	addConn    internet.ConnHandler
+	listener     net.Listener
+	tlsConfig    *gotls.Config
+	certificates common.Closable
+	authConfig   internet.ConnectionAuthenticator
+	config       *Config
+	addConn      internet.ConnHandler
 }
 
 func ListenTCP(ctx context.Context, address net.Address, port net.Port, streamSettings *internet.MemoryStreamConfig, handler internet.ConnHandler) (internet.Listener, error) {
@@ -35,15 +39,40 @@ func ListenTCP(ctx context.Context, address net.Address, port net.Port, streamSe
 		return nil, newError("failed to listen TCP on ", address, ":", port).Base(err)
 	}
 	newError("listening TCP on ", address, ":", port).WriteToLog(session.ExportIDToError(ctx))
+	if tcpSettings.AcceptProxyProtocol {
+		listener = proxyproto.NewListener(listener)
+		newError("accepting PROXY protocol on ", address, ":", port).AtDebug().WriteToLog(session.ExportIDToError(ctx))
+	}
 	l.listener = listener
+	if tcpSettings.HeaderSettings != nil {
+		headerConfig, err := tcpSettings.HeaderSettings.GetInstance()
+		if err != nil {
+			listener.Close()
+			return nil, newError("failed to get header settings").Base(err).AtError()
+		}
+		auth, err := internet.CreateConnectionAuthenticator(headerConfig)
+		if err != nil {
+			listener.Close()
+			return nil, newError("failed to create header authenticator").Base(err).AtError()
+		}
+		l.authConfig = auth
+	}
+	if config := tls.ConfigFromStreamSettings(streamSettings); config != nil {
+		l.tlsConfig, l.certificates = config.GetServerTLSConfig()
+	}
 	go l.keepAccepting()
-	return nil, nil
+	return l, nil
 }
 
 func (v *Listener) Addr() net.Addr {
 	return v.listener.Addr()
 }
 
+func (v *Listener) Close() error {
+	common.Close(v.certificates)
+	return v.listener.Close()
+}
+
 func (v *Listener) keepAccepting() {
 	for {
 		conn, err := v.listener.Accept()
@@ -58,6 +87,12 @@ func (v *Listener) keepAccepting() {
 			}
 			continue
 		}
+		if v.tlsConfig != nil {
+			conn = tls.Server(conn, v.tlsConfig)
+		}
+		if v.authConfig != nil {
+			conn = v.authConfig.Server(conn)
+		}
 		v.addConn(internet.Connection(conn))
 	}
 }
@@ -0,0 +1,210 @@
+package tls
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"os"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/vmessocket/vmessocket/common"
+	"github.com/vmessocket/vmessocket/common/task"
+)
+
+const certificateWatchInterval = 10 * time.Second
+
+var certificateWatchers = struct {
+	sync.Mutex
+	watchers map[string]*certificateWatcher
+}{
+	watchers: make(map[string]*certificateWatcher),
+}
+
+type certificateResolver struct {
+	static    []tls.Certificate
+	watched   []*certificateWatcher
+	issuer    *certificateIssuer
+	closeOnce sync.Once
+}
+
+type certificateWatcher struct {
+	sync.Mutex
+	key         string
+	refs        int
+	certFile    string
+	keyFile     string
+	certModTime time.Time
+	certSize    int64
+	keyModTime  time.Time
+	keySize     int64
+	current     atomic.Value
+	task        *task.Periodic
+}
+
+func getCertificateWatcher(certFile string, keyFile string, fallback *Certificate) *certificateWatcher {
+	key := certFile + "\x00" + keyFile
+	certificateWatchers.Lock()
+	defer certificateWatchers.Unlock()
+	if w, found := certificateWatchers.watchers[key]; found {
+		w.refs++
+		return w
+	}
+	w := &certificateWatcher{
+		key:      key,
+		refs:     1,
+		certFile: certFile,
+		keyFile:  keyFile,
+	}
+	if err := w.reload(true); err != nil {
+		newError("failed to load certificate ", certFile).Base(err).AtWarning().WriteToLog()
+		certificate, err := loadCertificate(fallback.Certificate, fallback.Key)
+		if err != nil {
+			newError("ignoring invalid X509 key pair").Base(err).AtWarning().WriteToLog()
+			return nil
+		}
+		w.current.Store(certificate)
+	}
+	w.task = &task.Periodic{
+		Interval: certificateWatchInterval,
+		Execute: func() error {
+			if err := w.reload(false); err != nil {
+				newError("failed to reload certificate ", w.certFile, ", keeping the previous one").Base(err).AtWarning().WriteToLog()
+			}
+			return nil
+		},
+	}
+	if err := w.task.Start(); err != nil {
+		newError("failed to watch certificate ", certFile).Base(err).AtWarning().WriteToLog()
+	}
+	certificateWatchers.watchers[key] = w
+	return w
+}
+
+func loadCertificate(certPEM []byte, keyPEM []byte) (*tls.Certificate, error) {
+	certificate, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return nil, err
+	}
+	certificate.Leaf, err = x509.ParseCertificate(certificate.Certificate[0])
+	if err != nil {
+		return nil, err
+	}
+	if time.Now().After(certificate.Leaf.NotAfter) {
+		return nil, newError("certificate expired at ", certificate.Leaf.NotAfter.Format(time.RFC3339))
+	}
+	return &certificate, nil
+}
+
+func loadCertificateFile(certFile string, keyFile string) (*tls.Certificate, error) {
+	certPEM, err := os.ReadFile(certFile)
+	if err != nil {
+		return nil, err
+	}
+	keyPEM, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, err
+	}
+	return loadCertificate(certPEM, keyPEM)
+}
+
+func ReloadCertificates() ([]string, []string) {
+	certificateWatchers.Lock()
+	watchers := make([]*certificateWatcher, 0, len(certificateWatchers.watchers))
+	for _, w := range certificateWatchers.watchers {
+		watchers = append(watchers, w)
+	}
+	certificateWatchers.Unlock()
+	sort.Slice(watchers, func(i, j int) bool {
+		return watchers[i].certFile < watchers[j].certFile
+	})
+	var reloaded []string
+	var failed []string
+	for _, w := range watchers {
+		if err := w.reload(true); err != nil {
+			newError("failed to reload certificate ", w.certFile, ", keeping the previous one").Base(err).AtWarning().WriteToLog()
+			failed = append(failed, w.certFile+": "+err.Error())
+			continue
+		}
+		reloaded = append(reloaded, w.certFile)
+	}
+	return reloaded, failed
+}
+
+func (w *certificateWatcher) Certificate() *tls.Certificate {
+	certificate, _ := w.current.Load().(*tls.Certificate)
+	return certificate
+}
+
+func (r *certificateResolver) Close() error {
+	r.closeOnce.Do(func() {
+		for _, w := range r.watched {
+			common.Must(w.Close())
+		}
+	})
+	return nil
+}
+
+func (w *certificateWatcher) Close() error {
+	certificateWatchers.Lock()
+	defer certificateWatchers.Unlock()
+	w.refs--
+	if w.refs > 0 {
+		return nil
+	}
+	delete(certificateWatchers.watchers, w.key)
+	return w.task.Close()
+}
+
+func (r *certificateResolver) GetCertificate(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	certificates := make([]*tls.Certificate, 0, len(r.static)+len(r.watched))
+	for idx := range r.static {
+		certificates = append(certificates, &r.static[idx])
+	}
+	for _, w := range r.watched {
+		if certificate := w.Certificate(); certificate != nil {
+			certificates = append(certificates, certificate)
+		}
+	}
+	for _, certificate := range certificates {
+		if hello.SupportsCertificate(certificate) == nil {
+			return certificate, nil
+		}
+	}
+	if r.issuer != nil && len(hello.ServerName) > 0 {
+		return r.issuer.issue(hello.ServerName)
+	}
+	if len(certificates) > 0 {
+		return certificates[0], nil
+	}
+	return nil, newError("no certificate available for ", hello.ServerName)
+}
+
+func (w *certificateWatcher) reload(force bool) error {
+	w.Lock()
+	defer w.Unlock()
+	certInfo, err := os.Stat(w.certFile)
+	if err != nil {
+		return err
+	}
+	keyInfo, err := os.Stat(w.keyFile)
+	if err != nil {
+		return err
+	}
+	if !force && certInfo.ModTime().Equal(w.certModTime) && certInfo.Size() == w.certSize &&
+		keyInfo.ModTime().Equal(w.keyModTime) && keyInfo.Size() == w.keySize {
+		return nil
+	}
+	w.certModTime = certInfo.ModTime()
+	w.certSize = certInfo.Size()
+	w.keyModTime = keyInfo.ModTime()
+	w.keySize = keyInfo.Size()
+	certificate, err := loadCertificateFile(w.certFile, w.keyFile)
+	if err != nil {
+		return err
+	}
+	w.current.Store(certificate)
+	newError("loaded certificate ", w.certFile, " valid until ", certificate.Leaf.NotAfter.Format(time.RFC3339)).AtInfo().WriteToLog()
+	return nil
+}
@@ -0,0 +1,113 @@
+package tls
+
+import (
+	"crypto/tls"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/vmessocket/vmessocket/common"
+	"github.com/vmessocket/vmessocket/common/protocol/tls/cert"
+)
+
+func writeCertificate(t *testing.T, dir string, name string) (string, string) {
+	t.Helper()
+	certPEM, keyPEM := cert.MustGenerate(nil, cert.CommonName(name)).ToPEM()
+	certFile := filepath.Join(dir, name+".crt")
+	keyFile := filepath.Join(dir, name+".key")
+	common.Must(os.WriteFile(certFile, certPEM, 0o600))
+	common.Must(os.WriteFile(keyFile, keyPEM, 0o600))
+	return certFile, keyFile
+}
+
+func watchedConfig(certFile string, keyFile string) *Config {
+	return &Config{
+		Certificate: []*Certificate{
+			{
+				Usage:           Certificate_ENCIPHERMENT,
+				CertificateFile: certFile,
+				KeyFile:         keyFile,
+			},
+		},
+	}
+}
+
+func watcherRefs(certFile string, keyFile string) int {
+	certificateWatchers.Lock()
+	defer certificateWatchers.Unlock()
+	w, found := certificateWatchers.watchers[certFile+"\x00"+keyFile]
+	if !found {
+		return 0
+	}
+	return w.refs
+}
+
+func TestCertificateWatcherRelease(t *testing.T) {
+	certFile, keyFile := writeCertificate(t, t.TempDir(), "release")
+	config := watchedConfig(certFile, keyFile)
+	_, first := config.GetServerTLSConfig()
+	_, second := config.GetServerTLSConfig()
+	if refs := watcherRefs(certFile, keyFile); refs != 2 {
+		t.Fatal("expected 2 references, got ", refs)
+	}
+	config.GetTLSConfig()
+	if refs := watcherRefs(certFile, keyFile); refs != 2 {
+		t.Fatal("client config took a reference: ", refs)
+	}
+	common.Must(first.Close())
+	common.Must(first.Close())
+	if refs := watcherRefs(certFile, keyFile); refs != 1 {
+		t.Fatal("expected 1 reference, got ", refs)
+	}
+	common.Must(second.Close())
+	if refs := watcherRefs(certFile, keyFile); refs != 0 {
+		t.Fatal("watcher still registered after last release")
+	}
+}
+
+func TestReloadCertificates(t *testing.T) {
+	dir := t.TempDir()
+	goodCert, goodKey := writeCertificate(t, dir, "good")
+	badCert, badKey := writeCertificate(t, dir, "bad")
+	_, good := watchedConfig(goodCert, goodKey).GetServerTLSConfig()
+	defer good.Close()
+	_, bad := watchedConfig(badCert, badKey).GetServerTLSConfig()
+	defer bad.Close()
+	common.Must(os.WriteFile(badCert, []byte("invalid"), 0o600))
+	reloaded, failed := ReloadCertificates()
+	if len(reloaded) != 1 || reloaded[0] != goodCert {
+		t.Fatal("unexpected reloaded certificates: ", reloaded)
+	}
+	if len(failed) != 1 || !strings.HasPrefix(failed[0], badCert+": ") {
+		t.Fatal("unexpected failed certificates: ", failed)
+	}
+}
+
+func TestReloadKeepsCertificateOnParseError(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := writeCertificate(t, dir, "current")
+	config, closer := watchedConfig(certFile, keyFile).GetServerTLSConfig()
+	defer closer.Close()
+	servedName := func() string {
+		certificate, err := config.GetCertificate(&tls.ClientHelloInfo{ServerName: "current"})
+		common.Must(err)
+		return certificate.Leaf.Subject.CommonName
+	}
+	common.Must(os.WriteFile(certFile, []byte("invalid"), 0o600))
+	if _, failed := ReloadCertificates(); len(failed) != 1 {
+		t.Fatal("unexpected failed certificates: ", failed)
+	}
+	if name := servedName(); name != "current" {
+		t.Fatal("certificate was replaced by an unparsable file: ", name)
+	}
+	renewedCert, renewedKey := writeCertificate(t, dir, "renewed")
+	common.Must(os.Rename(renewedCert, certFile))
+	common.Must(os.Rename(renewedKey, keyFile))
+	if reloaded, _ := ReloadCertificates(); len(reloaded) != 1 {
+		t.Fatal("unexpected reloaded certificates: ", reloaded)
+	}
+	if name := servedName(); name != "renewed" {
+		t.Fatal("renewed certificate was not swapped in: ", name)
+	}
+}
@@ -0,0 +1,34 @@
+package command
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+
+	"github.com/vmessocket/vmessocket/common"
+	"github.com/vmessocket/vmessocket/transport/internet/tls"
+)
+
+type CertificateServer struct{}
+
+type service struct{}
+
+func (s *CertificateServer) mustEmbedUnimplementedCertificateServiceServer() {}
+
+func (s *service) Register(server *grpc.Server) {
+	RegisterCertificateServiceServer(server, &CertificateServer{})
+}
+
+func (s *CertificateServer) ReloadCertificates(ctx context.Context, request *ReloadCertificatesRequest) (*ReloadCertificatesResponse, error) {
+	reloaded, failed := tls.ReloadCertificates()
+	return &ReloadCertificatesResponse{
+		CertificateFile: reloaded,
+		Failure:         failed,
+	}, nil
+}
+
+func init() {
+	common.Must(common.RegisterConfig((*Config)(nil), func(ctx context.Context, cfg interface{}) (interface{}, error) {
+		return &service{}, nil
+	}))
+}
@@ -0,0 +1,266 @@
+package tls
+
+import (
+	"crypto/hmac"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"strings"
+
+	"github.com/vmessocket/vmessocket/common"
+	"github.com/vmessocket/vmessocket/common/net"
+	"github.com/vmessocket/vmessocket/common/protocol/tls/cert"
+	"github.com/vmessocket/vmessocket/transport/internet"
+)
+
+var globalSessionCache = tls.NewLRUClientSessionCache(128)
+
+type Option func(*tls.Config)
+
+func ConfigFromStreamSettings(settings *internet.MemoryStreamConfig) *Config {
+	if settings == nil {
+		return nil
+	}
+	config, ok := settings.SecuritySettings.(*Config)
+	if !ok {
+		return nil
+	}
+	return config
+}
+
+func ParseCipherSuites(s string) ([]uint16, error) {
+	if len(s) == 0 {
+		return nil, nil
+	}
+	suites := make(map[string]uint16)
+	for _, suite := range tls.CipherSuites() {
+		suites[suite.Name] = suite.ID
+	}
+	for _, suite := range tls.InsecureCipherSuites() {
+		suites[suite.Name] = suite.ID
+	}
+	var ids []uint16
+	for _, name := range strings.Split(s, ":") {
+		name = strings.TrimSpace(name)
+		if len(name) == 0 {
+			continue
+		}
+		id, found := suites[name]
+		if !found {
+			return nil, newError("unknown cipher suite: ", name)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func ParseVersion(s string) (uint16, error) {
+	switch s {
+	case "":
+		return 0, nil
+	case "1.0":
+		return tls.VersionTLS10, nil
+	case "1.1":
+		return tls.VersionTLS11, nil
+	case "1.2":
+		return tls.VersionTLS12, nil
+	case "1.3":
+		return tls.VersionTLS13, nil
+	default:
+		return 0, newError("unknown TLS version: ", s)
+	}
+}
+
+func WithDestination(dest net.Destination) Option {
+	return func(config *tls.Config) {
+		if dest.Address.Family().IsDomain() && config.ServerName == "" {
+			config.ServerName = dest.Address.Domain()
+		}
+	}
+}
+
+func WithNextProto(protocol ...string) Option {
+	return func(config *tls.Config) {
+		if len(config.NextProtos) == 0 {
+			config.NextProtos = protocol
+		}
+	}
+}
+
+func (c *Config) BuildCertificates() []tls.Certificate {
+	certs := make([]tls.Certificate, 0, len(c.Certificate))
+	for _, entry := range c.Certificate {
+		if entry.Usage != Certificate_ENCIPHERMENT || entry.isWatched() {
+			continue
+		}
+		keyPair, err := tls.X509KeyPair(entry.Certificate, entry.Key)
+		if err != nil {
+			newError("ignoring invalid X509 key pair").Base(err).AtWarning().WriteToLog()
+			continue
+		}
+		if keyPair.Leaf, err = x509.ParseCertificate(keyPair.Certificate[0]); err != nil {
+			newError("ignoring invalid X509 certificate").Base(err).AtWarning().WriteToLog()
+			continue
+		}
+		certs = append(certs, keyPair)
+	}
+	return certs
+}
+
+func (c *Config) buildTLSConfig(resolver *certificateResolver, opts []Option) *tls.Config {
+	root, err := c.getCertPool()
+	if err != nil {
+		newError("failed to load system root certificate").AtError().Base(err).WriteToLog()
+	}
+	config := &tls.Config{
+		ClientSessionCache:       globalSessionCache,
+		RootCAs:                  root,
+		InsecureSkipVerify:       c.AllowInsecure,
+		NextProtos:               c.NextProtocol,
+		SessionTicketsDisabled:   !c.EnableSessionResumption,
+		VerifyPeerCertificate:    c.verifyPeerCert,
+		PreferServerCipherSuites: c.PreferServerCipherSuites,
+	}
+	if len(resolver.watched) > 0 || resolver.issuer != nil {
+		config.GetCertificate = resolver.GetCertificate
+	} else {
+		config.Certificates = resolver.static
+	}
+	for _, opt := range opts {
+		opt(config)
+	}
+	if sn := c.ServerName; len(sn) > 0 {
+		config.ServerName = sn
+	}
+	if config.MinVersion, err = ParseVersion(c.MinVersion); err != nil {
+		newError("ignoring minimal TLS version").Base(err).AtWarning().WriteToLog()
+	}
+	if config.MaxVersion, err = ParseVersion(c.MaxVersion); err != nil {
+		newError("ignoring maximal TLS version").Base(err).AtWarning().WriteToLog()
+	}
+	if config.CipherSuites, err = ParseCipherSuites(c.CipherSuites); err != nil {
+		newError("ignoring cipher suites").Base(err).AtWarning().WriteToLog()
+	}
+	return config
+}
+
+func (c *Config) getCertPool() (*x509.CertPool, error) {
+	var pool *x509.CertPool
+	if c.DisableSystemRoot {
+		pool = x509.NewCertPool()
+	} else {
+		systemPool, err := x509.SystemCertPool()
+		if err != nil {
+			return nil, newError("system root CA is not available").Base(err)
+		}
+		pool = systemPool
+	}
+	for _, cert := range c.Certificate {
+		if cert.Usage != Certificate_AUTHORITY_VERIFY {
+			continue
+		}
+		if !pool.AppendCertsFromPEM(cert.Certificate) {
+			return nil, newError("failed to append authority certificate")
+		}
+	}
+	return pool, nil
+}
+
+func (c *Config) getIssuingAuthorities() []*issuingAuthority {
+	var authorities []*issuingAuthority
+	for _, entry := range c.Certificate {
+		if entry.Usage != Certificate_AUTHORITY_ISSUE {
+			continue
+		}
+		if len(entry.IssueDomain) == 0 {
+			newError("ignoring issuing authority without allowed domains").AtWarning().WriteToLog()
+			continue
+		}
+		authority, err := cert.ParseCertificate(entry.Certificate, entry.Key)
+		if err != nil {
+			newError("ignoring invalid issuing authority").Base(err).AtWarning().WriteToLog()
+			continue
+		}
+		domains := make([]string, 0, len(entry.IssueDomain))
+		for _, domain := range entry.IssueDomain {
+			domains = append(domains, normalizeDomain(domain))
+		}
+		authorities = append(authorities, &issuingAuthority{
+			certificate: authority,
+			domains:     domains,
+		})
+	}
+	return authorities
+}
+
+func (c *Config) GetServerTLSConfig(opts ...Option) (*tls.Config, common.Closable) {
+	if c == nil {
+		c = new(Config)
+	}
+	resolver := &certificateResolver{
+		static:  c.BuildCertificates(),
+		watched: c.getWatchedCertificates(),
+	}
+	if authorities := c.getIssuingAuthorities(); len(authorities) > 0 {
+		resolver.issuer = newCertificateIssuer(authorities)
+	}
+	return c.buildTLSConfig(resolver, opts), resolver
+}
+
+func (c *Config) GetTLSConfig(opts ...Option) *tls.Config {
+	if c == nil {
+		c = new(Config)
+	}
+	return c.buildTLSConfig(&certificateResolver{
+		static: append(c.BuildCertificates(), c.loadWatchedCertificates()...),
+	}, opts)
+}
+
+func (c *Config) getWatchedCertificates() []*certificateWatcher {
+	var watchers []*certificateWatcher
+	for _, entry := range c.Certificate {
+		if entry.Usage != Certificate_ENCIPHERMENT || !entry.isWatched() {
+			continue
+		}
+		if w := getCertificateWatcher(entry.CertificateFile, entry.KeyFile, entry); w != nil {
+			watchers = append(watchers, w)
+		}
+	}
+	return watchers
+}
+
+func (c *Certificate) isWatched() bool {
+	return len(c.CertificateFile) > 0 && len(c.KeyFile) > 0
+}
+
+func (c *Config) loadWatchedCertificates() []tls.Certificate {
+	var certs []tls.Certificate
+	for _, entry := range c.Certificate {
+		if entry.Usage != Certificate_ENCIPHERMENT || !entry.isWatched() {
+			continue
+		}
+		certificate, err := loadCertificateFile(entry.CertificateFile, entry.KeyFile)
+		if err != nil {
+			newError("failed to load certificate ", entry.CertificateFile).Base(err).AtWarning().WriteToLog()
+			if certificate, err = loadCertificate(entry.Certificate, entry.Key); err != nil {
+				newError("ignoring invalid X509 key pair").Base(err).AtWarning().WriteToLog()
+				continue
+			}
+		}
+		certs = append(certs, *certificate)
+	}
+	return certs
+}
+
+func (c *Config) verifyPeerCert(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error {
+	if len(c.PinnedPeerCertificateChainSha256) == 0 {
+		return nil
+	}
+	hashValue := GenerateCertChainHash(rawCerts)
+	for _, v := range c.PinnedPeerCertificateChainSha256 {
+		if hmac.Equal(hashValue, v) {
+			return nil
+		}
+	}
+	return newError("peer certificate is unrecognized: ", base64.StdEncoding.EncodeToString(hashValue))
+}
@@ -0,0 +1,39 @@
+package tls
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGetTLSConfigNextProtos(t *testing.T) {
+	testCases := []struct {
+		name     string
+		config   *Config
+		opts     []Option
+		expected []string
+	}{
+		{
+			name:   "raw tcp",
+			config: &Config{},
+		},
+		{
+			name:     "h2 transport",
+			config:   &Config{},
+			opts:     []Option{WithNextProto("h2")},
+			expected: []string{"h2"},
+		},
+		{
+			name:     "configured alpn",
+			config:   &Config{NextProtocol: []string{"http/1.1"}},
+			opts:     []Option{WithNextProto("h2")},
+			expected: []string{"http/1.1"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if actual := tc.config.GetTLSConfig(tc.opts...).NextProtos; !reflect.DeepEqual(actual, tc.expected) {
+				t.Fatalf("expected %v, got %v", tc.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package tls
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vmessocket/vmessocket/common/cache"
+	"github.com/vmessocket/vmessocket/common/protocol/tls/cert"
+)
+
+const (
+	issuedCertificateLifetime = 24 * time.Hour
+	issuedCertificateRenewal  = time.Hour
+	maxIssuedCertificates     = 1024
+)
+
+type certificateIssuer struct {
+	sync.Mutex
+	authorities []*issuingAuthority
+	issued      cache.Lru
+	pending     map[string]*pendingIssue
+}
+
+type issuingAuthority struct {
+	certificate *cert.Certificate
+	domains     []string
+}
+
+type pendingIssue struct {
+	done        chan struct{}
+	certificate *tls.Certificate
+	err         error
+}
+
+func issueCertificate(authority *cert.Certificate, domain string) (*tls.Certificate, error) {
+	generated, err := cert.Generate(authority,
+		cert.CommonName(domain),
+		cert.DNSNames(domain),
+		cert.NotAfter(time.Now().Add(issuedCertificateLifetime)))
+	if err != nil {
+		return nil, err
+	}
+	certPEM, keyPEM := generated.ToPEM()
+	certificate, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return nil, err
+	}
+	certificate.Leaf, err = x509.ParseCertificate(certificate.Certificate[0])
+	if err != nil {
+		return nil, err
+	}
+	certificate.Certificate = append(certificate.Certificate, authority.Certificate)
+	return &certificate, nil
+}
+
+func newCertificateIssuer(authorities []*issuingAuthority) *certificateIssuer {
+	return &certificateIssuer{
+		authorities: authorities,
+		issued:      cache.NewLru(maxIssuedCertificates),
+		pending:     make(map[string]*pendingIssue),
+	}
+}
+
+func normalizeDomain(domain string) string {
+	return strings.ToLower(strings.TrimSuffix(domain, "."))
+}
+
+func (a *issuingAuthority) allows(domain string) bool {
+	for _, allowed := range a.domains {
+		if suffix := strings.TrimPrefix(allowed, "*"); len(suffix) < len(allowed) {
+			if strings.HasSuffix(domain, suffix) && len(domain) > len(suffix) {
+				return true
+			}
+		} else if domain == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+func (i *certificateIssuer) generate(domain string) (*tls.Certificate, error) {
+	for _, authority := range i.authorities {
+		if !authority.allows(domain) {
+			continue
+		}
+		certificate, err := issueCertificate(authority.certificate, domain)
+		if err != nil {
+			return nil, newError("failed to issue certificate for ", domain).Base(err)
+		}
+		newError("issued certificate for ", domain, " valid until ", certificate.Leaf.NotAfter.Format(time.RFC3339)).AtInfo().WriteToLog()
+		return certificate, nil
+	}
+	return nil, newError("server name is not allowed for issuing: ", domain)
+}
+
+func (i *certificateIssuer) issue(serverName string) (*tls.Certificate, error) {
+	domain := normalizeDomain(serverName)
+	if len(domain) == 0 {
+		return nil, newError("unable to issue certificate without server name")
+	}
+	i.Lock()
+	if v, found := i.issued.Get(domain); found {
+		certificate := v.(*tls.Certificate)
+		if time.Now().Add(issuedCertificateRenewal).Before(certificate.Leaf.NotAfter) {
+			i.Unlock()
+			return certificate, nil
+		}
+	}
+	if p, found := i.pending[domain]; found {
+		i.Unlock()
+		<-p.done
+		return p.certificate, p.err
+	}
+	p := &pendingIssue{done: make(chan struct{})}
+	i.pending[domain] = p
+	i.Unlock()
+	p.certificate, p.err = i.generate(domain)
+	i.Lock()
+	if p.err == nil {
+		i.issued.Put(domain, p.certificate)
+	}
+	delete(i.pending, domain)
+	i.Unlock()
+	close(p.done)
+	return p.certificate, p.err
+}
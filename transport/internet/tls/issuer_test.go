@@ -0,0 +1,90 @@
+package tls
+
+import (
+	"crypto/tls"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/vmessocket/vmessocket/common"
+	"github.com/vmessocket/vmessocket/common/protocol/tls/cert"
+)
+
+func newTestIssuer(domains ...string) *certificateIssuer {
+	ca, err := cert.Generate(nil, cert.Authority(true), cert.CommonName("issuer test CA"))
+	common.Must(err)
+	return newCertificateIssuer([]*issuingAuthority{{certificate: ca, domains: domains}})
+}
+
+func TestIssuerAllowlist(t *testing.T) {
+	issuer := newTestIssuer("example.com", "*.internal.test")
+	testCases := []struct {
+		serverName string
+		allowed    bool
+	}{
+		{"example.com", true},
+		{"EXAMPLE.com.", true},
+		{"www.example.com", false},
+		{"api.internal.test", true},
+		{"a.b.internal.test", true},
+		{"internal.test", false},
+		{"evilinternal.test", false},
+		{"", false},
+	}
+	for _, tc := range testCases {
+		certificate, err := issuer.issue(tc.serverName)
+		if tc.allowed {
+			if err != nil {
+				t.Errorf("%q: %v", tc.serverName, err)
+				continue
+			}
+			if err := certificate.Leaf.VerifyHostname(tc.serverName); err != nil {
+				t.Errorf("%q: %v", tc.serverName, err)
+			}
+		} else if err == nil {
+			t.Errorf("%q: issued a certificate outside the allowlist", tc.serverName)
+		}
+	}
+}
+
+func TestIssuerEvictsLeastRecentlyUsed(t *testing.T) {
+	issuer := newTestIssuer("*.example.com")
+	first, err := issuer.issue("first.example.com")
+	common.Must(err)
+	for idx := 1; idx < maxIssuedCertificates; idx++ {
+		issuer.issued.Put(fmt.Sprint(idx, ".example.com"), first)
+	}
+	if again, _ := issuer.issue("first.example.com"); again != first {
+		t.Fatal("cached certificate was reissued")
+	}
+	issuer.issued.Put("last.example.com", first)
+	if _, found := issuer.issued.Get("first.example.com"); !found {
+		t.Error("recently used certificate was evicted")
+	}
+	if _, found := issuer.issued.Get("1.example.com"); found {
+		t.Error("least recently used certificate was kept")
+	}
+}
+
+func TestIssuerSharesConcurrentIssues(t *testing.T) {
+	issuer := newTestIssuer("*.example.com")
+	certificates := make([]*tls.Certificate, 8)
+	var wg sync.WaitGroup
+	for idx := range certificates {
+		wg.Add(1)
+		go func(idx int) {
+			defer wg.Done()
+			certificate, err := issuer.issue("www.example.com")
+			if err != nil {
+				t.Error(err)
+			}
+			certificates[idx] = certificate
+		}(idx)
+	}
+	wg.Wait()
+	for _, certificate := range certificates {
+		if certificate == nil || certificate != certificates[0] {
+			t.Fatal("concurrent handshakes for one name got different certificates")
+		}
+	}
+}
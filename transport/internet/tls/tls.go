@@ -0,0 +1,31 @@
+package tls
+
+import (
+	"crypto/tls"
+
+	"github.com/vmessocket/vmessocket/common/buf"
+	"github.com/vmessocket/vmessocket/common/net"
+)
+
+//go:generate go run github.com/vmessocket/vmessocket/common/errors/errorgen
+
+type Conn struct {
+	*tls.Conn
+}
+
+func Client(c net.Conn, config *tls.Config) net.Conn {
+	tlsConn := tls.Client(c, config)
+	return &Conn{Conn: tlsConn}
+}
+
+func Server(c net.Conn, config *tls.Config) net.Conn {
+	tlsConn := tls.Server(c, config)
+	return &Conn{Conn: tlsConn}
+}
+
+func (c *Conn) WriteMultiBuffer(mb buf.MultiBuffer) error {
+	mb = buf.Compact(mb)
+	mb, err := buf.WriteMultiBuffer(c, mb)
+	buf.ReleaseMulti(mb)
+	return err
+}
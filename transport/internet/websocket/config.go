@@ -1,7 +1,11 @@
 package websocket
 
 import (
+	"io"
 	"net/http"
+	"os"
+	"path"
+	"strings"
 
 	"github.com/vmessocket/vmessocket/common"
 	"github.com/vmessocket/vmessocket/transport/internet"
@@ -9,6 +13,31 @@ import (
 
 const protocolName = "websocket"
 
+type staticFileSystem struct {
+	http.FileSystem
+}
+
+func (c *Config) getFallbackHandler() http.Handler {
+	fallback := c.Fallback
+	if fallback == nil {
+		return nil
+	}
+	if len(fallback.Directory) > 0 {
+		return http.FileServer(staticFileSystem{http.Dir(fallback.Directory)})
+	}
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if len(fallback.ContentType) > 0 {
+			writer.Header().Set("Content-Type", fallback.ContentType)
+		}
+		status := int(fallback.Status)
+		if status == 0 {
+			status = http.StatusOK
+		}
+		writer.WriteHeader(status)
+		io.WriteString(writer, fallback.Content)
+	})
+}
+
 func (c *Config) GetNormalizedPath() string {
 	path := c.Path
 	if path == "" {
@@ -25,9 +54,38 @@ func (c *Config) GetRequestHeader() http.Header {
 	for _, h := range c.Header {
 		header.Add(h.Key, h.Value)
 	}
+	if len(c.Host) > 0 {
+		header.Set("Host", c.Host)
+	}
 	return header
 }
 
+func (fs staticFileSystem) Open(name string) (http.File, error) {
+	for _, element := range strings.Split(name, "/") {
+		if strings.HasPrefix(element, ".") {
+			return nil, os.ErrNotExist
+		}
+	}
+	file, err := fs.FileSystem.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	if info.IsDir() {
+		index, err := fs.FileSystem.Open(path.Join(name, "index.html"))
+		if err != nil {
+			file.Close()
+			return nil, os.ErrNotExist
+		}
+		index.Close()
+	}
+	return file, nil
+}
+
 func init() {
 	common.Must(internet.RegisterProtocolConfigCreator(protocolName, func() interface{} {
 		return new(Config)
@@ -0,0 +1,50 @@
+package websocket
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/vmessocket/vmessocket/common"
+)
+
+func TestFallbackDirectory(t *testing.T) {
+	dir := t.TempDir()
+	common.Must(os.MkdirAll(filepath.Join(dir, "site"), 0o700))
+	common.Must(os.MkdirAll(filepath.Join(dir, "private"), 0o700))
+	common.Must(os.MkdirAll(filepath.Join(dir, ".git"), 0o700))
+	common.Must(os.WriteFile(filepath.Join(dir, "index.html"), []byte("home"), 0o600))
+	common.Must(os.WriteFile(filepath.Join(dir, "site", "index.html"), []byte("site"), 0o600))
+	common.Must(os.WriteFile(filepath.Join(dir, "private", "secret.txt"), []byte("secret"), 0o600))
+	common.Must(os.WriteFile(filepath.Join(dir, ".env"), []byte("token"), 0o600))
+	common.Must(os.WriteFile(filepath.Join(dir, ".git", "config"), []byte("config"), 0o600))
+	handler := (&Config{Fallback: &Fallback{Directory: dir}}).getFallbackHandler()
+	testCases := []struct {
+		path   string
+		status int
+		body   string
+	}{
+		{path: "/", status: http.StatusOK, body: "home"},
+		{path: "/site/", status: http.StatusOK, body: "site"},
+		{path: "/private/secret.txt", status: http.StatusOK, body: "secret"},
+		{path: "/private/", status: http.StatusNotFound},
+		{path: "/.env", status: http.StatusNotFound},
+		{path: "/.git/config", status: http.StatusNotFound},
+		{path: "/.git/", status: http.StatusNotFound},
+		{path: "/missing", status: http.StatusNotFound},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.path, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, tc.path, nil))
+			if recorder.Code != tc.status {
+				t.Fatalf("expected status %d, got %d", tc.status, recorder.Code)
+			}
+			if tc.status == http.StatusOK && recorder.Body.String() != tc.body {
+				t.Fatalf("expected %q, got %q", tc.body, recorder.Body.String())
+			}
+		})
+	}
+}
@@ -54,23 +54,6 @@ func newConnectionWithEarlyData(conn *websocket.Conn, remoteAddr net.Addr, early
 	}
 }
 
-func newRelayedConnection(conn io.ReadWriteCloser) *connectionForwarder {
-	return &connectionForwarder{
-		ReadWriteCloser: conn,
-		shouldWait:      false,
-	}
-}
-
-func newRelayedConnectionWithDelayedDial(dialer DelayedDialerForwarded) *connectionForwarder {
-	delayedDialContext, CancellFunc := context.WithCancel(context.Background())
-	return &connectionForwarder{
-		shouldWait:        true,
-		delayedDialFinish: delayedDialContext,
-		finishedDial:      CancellFunc,
-		dialer:            dialer,
-	}
-}
-
 func (c *connection) Close() error {
 	if c.shouldWait {
 		<-c.delayedDialFinish.Done()
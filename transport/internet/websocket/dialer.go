@@ -14,6 +14,7 @@ import (
 	"github.com/vmessocket/vmessocket/common/net"
 	"github.com/vmessocket/vmessocket/common/session"
 	"github.com/vmessocket/vmessocket/transport/internet"
+	"github.com/vmessocket/vmessocket/transport/internet/tls"
 )
 
 type dialerWithEarlyData struct {
@@ -22,11 +23,6 @@ type dialerWithEarlyData struct {
 	config  *Config
 }
 
-type dialerWithEarlyDataRelayed struct {
-	uriBase string
-	config  *Config
-}
-
 func Dial(ctx context.Context, dest net.Destination, streamSettings *internet.MemoryStreamConfig) (internet.Connection, error) {
 	newError("creating connection to ", dest).WriteToLog(session.ExportIDToError(ctx))
 	conn, err := dialWebsocket(ctx, dest, streamSettings)
@@ -47,11 +43,22 @@ func dialWebsocket(ctx context.Context, dest net.Destination, streamSettings *in
 		HandshakeTimeout: time.Second * 8,
 	}
 	protocol := "ws"
+	if config := tls.ConfigFromStreamSettings(streamSettings); config != nil {
+		protocol = "wss"
+		dialer.TLSClientConfig = config.GetTLSConfig(tls.WithDestination(dest), tls.WithNextProto("http/1.1"))
+	}
 	host := dest.NetAddr()
 	if (protocol == "ws" && dest.Port == 80) || (protocol == "wss" && dest.Port == 443) {
 		host = dest.Address.String()
 	}
 	uri := protocol + "://" + host + wsSettings.GetNormalizedPath()
+	if wsSettings.MaxEarlyData > 0 {
+		return newConnectionWithDelayedDial(&dialerWithEarlyData{
+			dialer:  dialer,
+			uriBase: uri,
+			config:  wsSettings,
+		}), nil
+	}
 	conn, resp, err := dialer.Dial(uri, wsSettings.GetRequestHeader())
 	if err != nil {
 		var reason string
@@ -66,12 +73,23 @@ func dialWebsocket(ctx context.Context, dest net.Destination, streamSettings *in
 func (d dialerWithEarlyData) Dial(earlyData []byte) (*websocket.Conn, error) {
 	earlyDataBuf := bytes.NewBuffer(nil)
 	base64EarlyDataEncoder := base64.NewEncoder(base64.RawURLEncoding, earlyDataBuf)
+	n, err := io.Copy(base64EarlyDataEncoder, io.LimitReader(bytes.NewReader(earlyData), int64(d.config.MaxEarlyData)))
+	if err != nil {
+		return nil, newError("websocket delayed dialer cannot encode early data").Base(err)
+	}
 	if errc := base64EarlyDataEncoder.Close(); errc != nil {
 		return nil, newError("websocket delayed dialer cannot encode early data tail").Base(errc)
 	}
 	dialFunction := func() (*websocket.Conn, *http.Response, error) {
 		return d.dialer.Dial(d.uriBase+earlyDataBuf.String(), d.config.GetRequestHeader())
 	}
+	if d.config.EarlyDataHeaderName != "" {
+		dialFunction = func() (*websocket.Conn, *http.Response, error) {
+			header := d.config.GetRequestHeader()
+			header.Set(d.config.EarlyDataHeaderName, earlyDataBuf.String())
+			return d.dialer.Dial(d.uriBase, header)
+		}
+	}
 	conn, resp, err := dialFunction()
 	if err != nil {
 		var reason string
@@ -80,16 +98,13 @@ func (d dialerWithEarlyData) Dial(earlyData []byte) (*websocket.Conn, error) {
 		}
 		return nil, newError("failed to dial to (", d.uriBase, ") with early data: ", reason).Base(err)
 	}
-	return conn, nil
-}
-
-func (d dialerWithEarlyDataRelayed) Dial(earlyData []byte) (io.ReadWriteCloser, error) {
-	earlyDataBuf := bytes.NewBuffer(nil)
-	base64EarlyDataEncoder := base64.NewEncoder(base64.RawURLEncoding, earlyDataBuf)
-	if errc := base64EarlyDataEncoder.Close(); errc != nil {
-		return nil, newError("websocket delayed dialer cannot encode early data tail").Base(errc)
+	if n != int64(len(earlyData)) {
+		if err := conn.WriteMessage(websocket.BinaryMessage, earlyData[n:]); err != nil {
+			conn.Close()
+			return nil, newError("failed to write remaining early data to (", d.uriBase, ")").Base(err)
+		}
 	}
-	return nil, nil
+	return conn, nil
 }
 
 func init() {
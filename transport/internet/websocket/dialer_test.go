@@ -0,0 +1,69 @@
+package websocket
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/vmessocket/vmessocket/common"
+	"github.com/vmessocket/vmessocket/common/net"
+	"github.com/vmessocket/vmessocket/transport/internet"
+)
+
+func TestDialEarlyData(t *testing.T) {
+	testCases := []struct {
+		name       string
+		headerName string
+		size       int
+	}{
+		{name: "path", size: 16},
+		{name: "path with remainder", size: 100},
+		{name: "header", headerName: "X-Early-Data", size: 16},
+		{name: "header with remainder", headerName: "X-Early-Data", size: 100},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			streamSettings := newStreamSettings("/ws", nil)
+			config := streamSettings.ProtocolSettings.(*Config)
+			config.MaxEarlyData = 64
+			config.EarlyDataHeaderName = tc.headerName
+			received := make(chan []byte, 1)
+			listener, err := ListenWS(context.Background(), net.LocalHostIP, 0, streamSettings, func(conn internet.Connection) {
+				defer conn.Close()
+				early, err := io.ReadAll(conn.(*connection).reader)
+				common.Must(err)
+				received <- early
+				conn.Write(early)
+				io.Copy(conn, conn)
+			})
+			common.Must(err)
+			defer listener.Close()
+			conn, err := Dial(context.Background(), net.DestinationFromAddr(listener.Addr()), streamSettings)
+			common.Must(err)
+			defer conn.Close()
+			select {
+			case <-received:
+				t.Fatal("connection was opened before the first write")
+			case <-time.After(100 * time.Millisecond):
+			}
+			payload := bytes.Repeat([]byte{'x'}, tc.size)
+			common.Must2(conn.Write(payload))
+			early := <-received
+			want := payload
+			if len(want) > int(config.MaxEarlyData) {
+				want = want[:config.MaxEarlyData]
+			}
+			if !bytes.Equal(early, want) {
+				t.Fatalf("early data carried %d bytes, want %d", len(early), len(want))
+			}
+			echo := make([]byte, tc.size)
+			conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+			common.Must2(io.ReadFull(conn, echo))
+			if !bytes.Equal(echo, payload) {
+				t.Fatal("echoed data differs")
+			}
+		})
+	}
+}
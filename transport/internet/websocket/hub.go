@@ -3,6 +3,7 @@ package websocket
 import (
 	"bytes"
 	"context"
+	gotls "crypto/tls"
 	"encoding/base64"
 	"io"
 	"net/http"
@@ -11,115 +12,289 @@ import (
 	"time"
 
 	"github.com/gorilla/websocket"
+	"google.golang.org/protobuf/proto"
 
 	"github.com/vmessocket/vmessocket/common"
 	"github.com/vmessocket/vmessocket/common/net"
 	http_proto "github.com/vmessocket/vmessocket/common/protocol/http"
+	"github.com/vmessocket/vmessocket/common/protocol/proxyproto"
 	"github.com/vmessocket/vmessocket/common/session"
 	"github.com/vmessocket/vmessocket/transport/internet"
+	"github.com/vmessocket/vmessocket/transport/internet/tls"
 )
 
-var upgrader = &websocket.Upgrader{
-	ReadBufferSize:   4 * 1024,
-	WriteBufferSize:  4 * 1024,
-	HandshakeTimeout: time.Second * 4,
-	CheckOrigin: func(r *http.Request) bool {
-		return true
-	},
-}
+var (
+	sharedServers = struct {
+		sync.Mutex
+		servers map[string]*sharedServer
+	}{
+		servers: make(map[string]*sharedServer),
+	}
+	upgrader = &websocket.Upgrader{
+		ReadBufferSize:   4 * 1024,
+		WriteBufferSize:  4 * 1024,
+		HandshakeTimeout: time.Second * 4,
+		CheckOrigin: func(r *http.Request) bool {
+			return true
+		},
+	}
+)
 
 type Listener struct {
-	sync.Mutex
-	server   http.Server
-	listener net.Listener
-	config   *Config
-	addConn  internet.ConnHandler
+	server  *sharedServer
+	handler *requestHandler
 }
 
 type requestHandler struct {
 	path                string
-	ln                  *Listener
+	host                string
+	allowedOrigins      []string
+	trustedProxies      []*net.IPNet
+	addConn             internet.ConnHandler
+	fallback            http.Handler
 	earlyDataEnabled    bool
 	earlyDataHeaderName string
 }
 
+type sharedServer struct {
+	sync.RWMutex
+	key           string
+	server        http.Server
+	listener      net.Listener
+	tlsSettings   *tls.Config
+	certificates  common.Closable
+	proxyProtocol bool
+	handlers      []*requestHandler
+}
+
 func ListenWS(ctx context.Context, address net.Address, port net.Port, streamSettings *internet.MemoryStreamConfig, addConn internet.ConnHandler) (internet.Listener, error) {
-	l := &Listener{
-		addConn: addConn,
-	}
 	wsSettings := streamSettings.ProtocolSettings.(*Config)
-	l.config = wsSettings
-	var listener net.Listener
-	var err error
-	listener, err = internet.ListenSystem(ctx, &net.TCPAddr{
+	trustedProxies, err := http_proto.ParseTrustedProxies(wsSettings.TrustedProxies)
+	if err != nil {
+		return nil, err
+	}
+	handler := &requestHandler{
+		path:                wsSettings.GetNormalizedPath(),
+		host:                wsSettings.Host,
+		allowedOrigins:      wsSettings.AllowedOrigins,
+		trustedProxies:      trustedProxies,
+		addConn:             addConn,
+		fallback:            wsSettings.getFallbackHandler(),
+		earlyDataEnabled:    wsSettings.MaxEarlyData > 0,
+		earlyDataHeaderName: wsSettings.EarlyDataHeaderName,
+	}
+	key := net.TCPDestination(address, port).NetAddr()
+	sharedServers.Lock()
+	defer sharedServers.Unlock()
+	if s, found := sharedServers.servers[key]; found {
+		if !proto.Equal(s.tlsSettings, tls.ConfigFromStreamSettings(streamSettings)) {
+			return nil, newError("conflicting TLS settings for WebSocket on ", key)
+		}
+		if s.proxyProtocol != wsSettings.AcceptProxyProtocol {
+			return nil, newError("conflicting PROXY protocol settings for WebSocket on ", key)
+		}
+		if err := s.add(handler); err != nil {
+			return nil, err
+		}
+		newError("adding WebSocket path ", handler.path, " on ", key).WriteToLog(session.ExportIDToError(ctx))
+		return &Listener{
+			server:  s,
+			handler: handler,
+		}, nil
+	}
+	s, err := newSharedServer(ctx, address, port, streamSettings)
+	if err != nil {
+		return nil, err
+	}
+	common.Must(s.add(handler))
+	sharedServers.servers[key] = s
+	newError("listening TCP(for WS) on ", address, ":", port, " with path ", handler.path).WriteToLog(session.ExportIDToError(ctx))
+	go func() {
+		if err := s.server.Serve(s.listener); err != nil && err != http.ErrServerClosed {
+			newError("stopped serving WebSocket on ", key).Base(err).AtWarning().WriteToLog(session.ExportIDToError(ctx))
+		}
+	}()
+	return &Listener{
+		server:  s,
+		handler: handler,
+	}, nil
+}
+
+func newSharedServer(ctx context.Context, address net.Address, port net.Port, streamSettings *internet.MemoryStreamConfig) (*sharedServer, error) {
+	listener, err := internet.ListenSystem(ctx, &net.TCPAddr{
 		IP:   address.IP(),
 		Port: int(port),
 	}, streamSettings.SocketSettings)
 	if err != nil {
 		return nil, newError("failed to listen TCP(for WS) on ", address, ":", port).Base(err)
 	}
-	newError("listening TCP(for WS) on ", address, ":", port).WriteToLog(session.ExportIDToError(ctx))
-	l.listener = listener
-	useEarlyData := false
-	earlyDataHeaderName := ""
-	l.server = http.Server{
-		Handler: &requestHandler{
-			path:                wsSettings.GetNormalizedPath(),
-			ln:                  l,
-			earlyDataEnabled:    useEarlyData,
-			earlyDataHeaderName: earlyDataHeaderName,
-		},
+	s := &sharedServer{
+		key: net.TCPDestination(address, port).NetAddr(),
+	}
+	if wsSettings := streamSettings.ProtocolSettings.(*Config); wsSettings.AcceptProxyProtocol {
+		listener = proxyproto.NewListener(listener)
+		s.proxyProtocol = true
+	}
+	if config := tls.ConfigFromStreamSettings(streamSettings); config != nil {
+		tlsConfig, certificates := config.GetServerTLSConfig(tls.WithNextProto("http/1.1"))
+		listener = gotls.NewListener(listener, tlsConfig)
+		s.tlsSettings = config
+		s.certificates = certificates
+	}
+	s.listener = listener
+	s.server = http.Server{
+		Handler:           s,
 		ReadHeaderTimeout: time.Second * 4,
 		MaxHeaderBytes:    http.DefaultMaxHeaderBytes,
 	}
-	return nil, err
+	return s, nil
+}
+
+func (s *sharedServer) add(handler *requestHandler) error {
+	s.Lock()
+	defer s.Unlock()
+	for _, h := range s.handlers {
+		if h.path == handler.path && strings.EqualFold(h.host, handler.host) {
+			return newError("WebSocket path ", handler.host, handler.path, " is already in use on ", s.key)
+		}
+	}
+	s.handlers = append(s.handlers, handler)
+	return nil
 }
 
 func (ln *Listener) Addr() net.Addr {
-	return ln.listener.Addr()
+	return ln.server.listener.Addr()
 }
 
-func (h *requestHandler) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
-	var earlyData io.Reader
-	if !h.earlyDataEnabled {
-		if request.URL.Path != h.path {
-			writer.WriteHeader(http.StatusNotFound)
-			return
+func (ln *Listener) Close() error {
+	sharedServers.Lock()
+	defer sharedServers.Unlock()
+	if ln.server.remove(ln.handler) > 0 {
+		return nil
+	}
+	delete(sharedServers.servers, ln.server.key)
+	common.Close(ln.server.certificates)
+	return ln.server.server.Close()
+}
+
+func (s *sharedServer) getHandler(request *http.Request) *requestHandler {
+	s.RLock()
+	defer s.RUnlock()
+	var matched *requestHandler
+	var matchedScore int
+	for _, h := range s.handlers {
+		if score := h.match(request); score > matchedScore {
+			matched = h
+			matchedScore = score
+		}
+	}
+	return matched
+}
+
+func (h *requestHandler) isAllowedOrigin(origin string) bool {
+	if len(h.allowedOrigins) == 0 {
+		return true
+	}
+	for _, allowed := range h.allowedOrigins {
+		if allowed == "*" || strings.EqualFold(allowed, origin) {
+			return true
 		}
-	} else if h.earlyDataHeaderName != "" {
-		if request.URL.Path != h.path {
-			writer.WriteHeader(http.StatusNotFound)
-			return
+	}
+	return false
+}
+
+func (h *requestHandler) isValidHost(host string) bool {
+	if len(h.host) == 0 || strings.EqualFold(h.host, host) {
+		return true
+	}
+	if hostname, _, err := net.SplitHostPort(host); err == nil {
+		return strings.EqualFold(h.host, hostname)
+	}
+	return false
+}
+
+func (h *requestHandler) match(request *http.Request) int {
+	if !h.isValidHost(request.Host) {
+		return 0
+	}
+	score := len(h.path) + 1
+	if len(h.host) > 0 {
+		score += 1 << 16
+	}
+	if request.URL.Path == h.path {
+		return score + 1<<17
+	}
+	if h.earlyDataEnabled && h.earlyDataHeaderName == "" && strings.HasPrefix(request.URL.RequestURI(), h.path) {
+		return score
+	}
+	return 0
+}
+
+func (s *sharedServer) remove(handler *requestHandler) int {
+	s.Lock()
+	defer s.Unlock()
+	for idx, h := range s.handlers {
+		if h == handler {
+			s.handlers = append(s.handlers[:idx], s.handlers[idx+1:]...)
+			break
 		}
-		earlyDataStr := request.Header.Get(h.earlyDataHeaderName)
-		earlyData = base64.NewDecoder(base64.RawURLEncoding, bytes.NewReader([]byte(earlyDataStr)))
-	} else {
-		if strings.HasPrefix(request.URL.RequestURI(), h.path) {
-			earlyDataStr := request.URL.RequestURI()[len(h.path):]
-			earlyData = base64.NewDecoder(base64.RawURLEncoding, bytes.NewReader([]byte(earlyDataStr)))
+	}
+	return len(s.handlers)
+}
+
+func (s *sharedServer) serveFallback(writer http.ResponseWriter, request *http.Request) {
+	var fallback http.Handler
+	s.RLock()
+	for _, h := range s.handlers {
+		if h.fallback != nil {
+			fallback = h.fallback
+			break
+		}
+	}
+	s.RUnlock()
+	if fallback == nil {
+		writer.WriteHeader(http.StatusNotFound)
+		return
+	}
+	fallback.ServeHTTP(writer, request)
+}
+
+func (h *requestHandler) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	var earlyData io.Reader
+	if h.earlyDataEnabled {
+		var earlyDataStr string
+		if h.earlyDataHeaderName != "" {
+			earlyDataStr = request.Header.Get(h.earlyDataHeaderName)
 		} else {
-			writer.WriteHeader(http.StatusNotFound)
-			return
+			earlyDataStr = request.URL.RequestURI()[len(h.path):]
 		}
+		earlyData = base64.NewDecoder(base64.RawURLEncoding, bytes.NewReader([]byte(earlyDataStr)))
+	}
+	if origin := request.Header.Get("Origin"); len(origin) > 0 && !h.isAllowedOrigin(origin) {
+		newError("rejected WebSocket request from origin ", origin).AtInfo().WriteToLog()
+		writer.WriteHeader(http.StatusForbidden)
+		return
 	}
 	conn, err := upgrader.Upgrade(writer, request, nil)
 	if err != nil {
 		newError("failed to convert to WebSocket connection").Base(err).WriteToLog()
 		return
 	}
-	forwardedAddrs := http_proto.ParseXForwardedFor(request.Header)
-	remoteAddr := conn.RemoteAddr()
-	if len(forwardedAddrs) > 0 && forwardedAddrs[0].Family().IsIP() {
-		remoteAddr = &net.TCPAddr{
-			IP:   forwardedAddrs[0].IP(),
-			Port: int(0),
-		}
-	}
+	remoteAddr := http_proto.ResolveRemoteAddr(request.Header, conn.RemoteAddr(), h.trustedProxies)
 	if earlyData == nil {
-		h.ln.addConn(newConnection(conn, remoteAddr))
+		h.addConn(newConnection(conn, remoteAddr))
 	} else {
-		h.ln.addConn(newConnectionWithEarlyData(conn, remoteAddr, earlyData))
+		h.addConn(newConnectionWithEarlyData(conn, remoteAddr, earlyData))
+	}
+}
+
+func (s *sharedServer) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	handler := s.getHandler(request)
+	if handler == nil || !websocket.IsWebSocketUpgrade(request) {
+		s.serveFallback(writer, request)
+		return
 	}
+	handler.ServeHTTP(writer, request)
 }
 
 func init() {
@@ -0,0 +1,205 @@
+package websocket
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/vmessocket/vmessocket/common"
+	"github.com/vmessocket/vmessocket/common/net"
+	"github.com/vmessocket/vmessocket/common/protocol/tls/cert"
+	"github.com/vmessocket/vmessocket/transport/internet"
+	"github.com/vmessocket/vmessocket/transport/internet/tls"
+)
+
+func newTLSConfig(name string, alpn ...string) *tls.Config {
+	certPEM, keyPEM := cert.MustGenerate(nil, cert.CommonName(name)).ToPEM()
+	return &tls.Config{
+		Certificate: []*tls.Certificate{
+			{
+				Certificate: certPEM,
+				Key:         keyPEM,
+				Usage:       tls.Certificate_ENCIPHERMENT,
+			},
+		},
+		NextProtocol: alpn,
+	}
+}
+
+func newStreamSettings(path string, security *tls.Config) *internet.MemoryStreamConfig {
+	settings := &internet.MemoryStreamConfig{
+		ProtocolName:     protocolName,
+		ProtocolSettings: &Config{Path: path},
+	}
+	if security != nil {
+		settings.SecurityType = "tls"
+		settings.SecuritySettings = security
+	}
+	return settings
+}
+
+func pickPort() net.Port {
+	probe, err := net.Listen("tcp", "127.0.0.1:0")
+	common.Must(err)
+	defer probe.Close()
+	return net.Port(probe.Addr().(*net.TCPAddr).Port)
+}
+
+func TestListenWSSharedPortTLS(t *testing.T) {
+	port := pickPort()
+	security := newTLSConfig("example.com")
+	first, err := ListenWS(context.Background(), net.LocalHostIP, port, newStreamSettings("/first", security), func(internet.Connection) {})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer first.Close()
+	other := newTLSConfig("example.org")
+	alpn := newTLSConfig("example.com", "http/1.1")
+	alpn.Certificate = security.Certificate
+	testCases := []struct {
+		name     string
+		security *tls.Config
+		err      bool
+	}{
+		{
+			name:     "same tls",
+			security: security,
+		},
+		{
+			name:     "other certificate",
+			security: other,
+			err:      true,
+		},
+		{
+			name:     "other alpn",
+			security: alpn,
+			err:      true,
+		},
+		{
+			name:     "other version",
+			security: &tls.Config{Certificate: security.Certificate, MinVersion: "1.3"},
+			err:      true,
+		},
+		{
+			name: "no tls",
+			err:  true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			listener, err := ListenWS(context.Background(), net.LocalHostIP, port, newStreamSettings("/"+tc.name, tc.security), func(internet.Connection) {})
+			if (err != nil) != tc.err {
+				t.Fatal("unexpected error: ", err)
+			}
+			if err == nil {
+				common.Must(listener.Close())
+			}
+		})
+	}
+}
+
+func TestListenWSSharedPortPaths(t *testing.T) {
+	accepted := make(chan string, 2)
+	port := pickPort()
+	listen := func(path string, fallback *Fallback) {
+		streamSettings := newStreamSettings(path, nil)
+		streamSettings.ProtocolSettings.(*Config).Fallback = fallback
+		listener, err := ListenWS(context.Background(), net.LocalHostIP, port, streamSettings, func(conn internet.Connection) {
+			accepted <- path
+			conn.Close()
+		})
+		common.Must(err)
+		t.Cleanup(func() {
+			listener.Close()
+		})
+	}
+	listen("/first", &Fallback{Status: http.StatusTeapot, Content: "not here"})
+	listen("/second", nil)
+	base := "ws://" + net.TCPDestination(net.LocalHostIP, port).NetAddr()
+	for _, path := range []string{"/second", "/first"} {
+		conn, _, err := websocket.DefaultDialer.Dial(base+path, nil)
+		common.Must(err)
+		conn.Close()
+		if got := <-accepted; got != path {
+			t.Errorf("request for %s reached the handler for %s", path, got)
+		}
+	}
+	response, err := http.Get("http://" + net.TCPDestination(net.LocalHostIP, port).NetAddr() + "/third")
+	common.Must(err)
+	defer response.Body.Close()
+	body, err := io.ReadAll(response.Body)
+	common.Must(err)
+	if response.StatusCode != http.StatusTeapot || string(body) != "not here" {
+		t.Errorf("unexpected fallback response %d %q", response.StatusCode, body)
+	}
+}
+
+func TestListenWSRequestChecks(t *testing.T) {
+	testCases := []struct {
+		name    string
+		config  *Config
+		header  http.Header
+		remote  string
+		refused bool
+	}{
+		{
+			name:   "forwarded header from untrusted peer",
+			config: &Config{},
+			header: http.Header{"X-Forwarded-For": {"203.0.113.7"}},
+			remote: "127.0.0.1",
+		},
+		{
+			name:   "forwarded header from trusted proxy",
+			config: &Config{TrustedProxies: []string{"127.0.0.0/8"}},
+			header: http.Header{"X-Forwarded-For": {"203.0.113.7"}},
+			remote: "203.0.113.7",
+		},
+		{
+			name:   "allowed origin",
+			config: &Config{AllowedOrigins: []string{"https://example.com"}},
+			header: http.Header{"Origin": {"https://EXAMPLE.com"}},
+			remote: "127.0.0.1",
+		},
+		{
+			name:    "foreign origin",
+			config:  &Config{AllowedOrigins: []string{"https://example.com"}},
+			header:  http.Header{"Origin": {"https://example.org"}},
+			refused: true,
+		},
+		{
+			name:    "other host",
+			config:  &Config{Host: "example.com"},
+			refused: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			remotes := make(chan string, 1)
+			listener, err := ListenWS(context.Background(), net.LocalHostIP, pickPort(), &internet.MemoryStreamConfig{
+				ProtocolName:     protocolName,
+				ProtocolSettings: tc.config,
+			}, func(conn internet.Connection) {
+				remotes <- conn.RemoteAddr().(*net.TCPAddr).IP.String()
+				conn.Close()
+			})
+			common.Must(err)
+			defer listener.Close()
+			conn, _, err := websocket.DefaultDialer.Dial("ws://"+listener.Addr().String()+"/", tc.header)
+			if tc.refused {
+				if err == nil {
+					conn.Close()
+					t.Fatal("upgrade was accepted")
+				}
+				return
+			}
+			common.Must(err)
+			conn.Close()
+			if remote := <-remotes; remote != tc.remote {
+				t.Errorf("remote address %s, want %s", remote, tc.remote)
+			}
+		})
+	}
+}